@@ -0,0 +1,21 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "audio.h"
+#include "_cgo_export.h"
+
+static void audioCallback(void *userdata, Uint8 *stream, int len) {
+	goAudioCallback((uintptr_t)userdata, stream, len);
+}
+
+SDL_AudioDeviceID openAudioDevice(const char *device, int iscapture,
+	SDL_AudioSpec *desired, SDL_AudioSpec *obtained, int allowed_changes,
+	uintptr_t handle) {
+	if (handle != 0) {
+		desired->callback = audioCallback;
+		desired->userdata = (void *)handle;
+	}
+	return SDL_OpenAudioDevice(device, iscapture, desired, obtained,
+		allowed_changes);
+}
@@ -0,0 +1,362 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+#include "audio.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// AudioFormat describes the size, type, and byte order of audio samples.
+type AudioFormat uint16
+
+const (
+	AUDIO_U8     AudioFormat = C.AUDIO_U8     // Unsigned 8-bit samples
+	AUDIO_S8     AudioFormat = C.AUDIO_S8     // Signed 8-bit samples
+	AUDIO_U16LSB AudioFormat = C.AUDIO_U16LSB // Unsigned 16-bit samples
+	AUDIO_S16LSB AudioFormat = C.AUDIO_S16LSB // Signed 16-bit samples
+	AUDIO_U16MSB AudioFormat = C.AUDIO_U16MSB // As above, but big-endian byte order
+	AUDIO_S16MSB AudioFormat = C.AUDIO_S16MSB // As above, but big-endian byte order
+	AUDIO_U16    AudioFormat = C.AUDIO_U16
+	AUDIO_S16    AudioFormat = C.AUDIO_S16
+	AUDIO_S32LSB AudioFormat = C.AUDIO_S32LSB // 32-bit integer samples
+	AUDIO_S32MSB AudioFormat = C.AUDIO_S32MSB // As above, but big-endian byte order
+	AUDIO_S32    AudioFormat = C.AUDIO_S32
+	AUDIO_F32LSB AudioFormat = C.AUDIO_F32LSB // 32-bit floating point samples
+	AUDIO_F32MSB AudioFormat = C.AUDIO_F32MSB // As above, but big-endian byte order
+	AUDIO_F32    AudioFormat = C.AUDIO_F32
+
+	// Native audio byte ordering
+	AUDIO_U16SYS AudioFormat = C.AUDIO_U16SYS
+	AUDIO_S16SYS AudioFormat = C.AUDIO_S16SYS
+	AUDIO_S32SYS AudioFormat = C.AUDIO_S32SYS
+	AUDIO_F32SYS AudioFormat = C.AUDIO_F32SYS
+)
+
+// BitSize returns the number of bits in a sample of format f.
+func (f AudioFormat) BitSize() int {
+	return int(f & C.SDL_AUDIO_MASK_BITSIZE)
+}
+
+// IsFloat returns true if the samples of format f are floating point.
+func (f AudioFormat) IsFloat() bool {
+	return f&C.SDL_AUDIO_MASK_DATATYPE != 0
+}
+
+// IsBigEndian returns true if the samples of format f are big-endian.
+func (f AudioFormat) IsBigEndian() bool {
+	return f&C.SDL_AUDIO_MASK_ENDIAN != 0
+}
+
+// IsSigned returns true if the samples of format f are signed.
+func (f AudioFormat) IsSigned() bool {
+	return f&C.SDL_AUDIO_MASK_SIGNED != 0
+}
+
+// Flags for the allowedChanges argument of OpenAudioDevice.
+const (
+	AUDIO_ALLOW_FREQUENCY_CHANGE = C.SDL_AUDIO_ALLOW_FREQUENCY_CHANGE
+	AUDIO_ALLOW_FORMAT_CHANGE    = C.SDL_AUDIO_ALLOW_FORMAT_CHANGE
+	AUDIO_ALLOW_CHANNELS_CHANGE  = C.SDL_AUDIO_ALLOW_CHANNELS_CHANGE
+	AUDIO_ALLOW_ANY_CHANGE       = C.SDL_AUDIO_ALLOW_ANY_CHANGE
+)
+
+// AudioSpec describes the audio format of a device or a buffer.  Silence and
+// Size are calculated by SDL and are ignored when a spec is passed to SDL.
+type AudioSpec struct {
+	Freq     int32       // Samples per second
+	Format   AudioFormat // Audio data format
+	Channels uint8       // Number of channels: 1 mono, 2 stereo
+	Silence  uint8       // Audio buffer silence value
+	Samples  uint16      // Audio buffer size in sample frames, a power of 2
+	Size     uint32      // Audio buffer size in bytes
+}
+
+func (spec *AudioSpec) cspec() C.SDL_AudioSpec {
+	return C.SDL_AudioSpec{
+		freq:     C.int(spec.Freq),
+		format:   C.SDL_AudioFormat(spec.Format),
+		channels: C.Uint8(spec.Channels),
+		samples:  C.Uint16(spec.Samples),
+	}
+}
+
+func goAudioSpec(cspec *C.SDL_AudioSpec) *AudioSpec {
+	return &AudioSpec{
+		Freq:     int32(cspec.freq),
+		Format:   AudioFormat(cspec.format),
+		Channels: uint8(cspec.channels),
+		Silence:  uint8(cspec.silence),
+		Samples:  uint16(cspec.samples),
+		Size:     uint32(cspec.size),
+	}
+}
+
+// AudioDeviceID identifies an audio device opened with OpenAudioDevice.
+type AudioDeviceID uint32
+
+type AudioStatus int
+
+const (
+	AUDIO_STOPPED AudioStatus = C.SDL_AUDIO_STOPPED
+	AUDIO_PLAYING AudioStatus = C.SDL_AUDIO_PLAYING
+	AUDIO_PAUSED  AudioStatus = C.SDL_AUDIO_PAUSED
+)
+
+// AudioSource supplies the samples of an audio device opened with a source.
+// FillAudio is called on the audio thread whenever the device needs more
+// audio.  stream is filled with silence beforehand, FillAudio writes up to
+// len(stream) bytes in the format of the device into it.  For capture devices
+// stream holds the recorded audio instead.
+//
+// stream is only valid until FillAudio returns.  FillAudio should return
+// quickly, the device plays silence or drops samples while it runs late.
+type AudioSource interface {
+	FillAudio(stream []byte)
+}
+
+// AudioSourceFunc is a function that is an AudioSource.
+type AudioSourceFunc func(stream []byte)
+
+// FillAudio calls f(stream).
+func (f AudioSourceFunc) FillAudio(stream []byte) {
+	f(stream)
+}
+
+// AudioReader returns an AudioSource that reads samples from r.  When r
+// returns less data than the device asks for, the rest is silence.
+func AudioReader(r io.Reader) AudioSource {
+	return AudioSourceFunc(func(stream []byte) {
+		io.ReadFull(r, stream)
+	})
+}
+
+// audioDevice is the Go side of a device opened with an AudioSource.
+type audioDevice struct {
+	source  AudioSource
+	silence byte
+	capture bool // stream holds recorded audio, do not overwrite it
+}
+
+// audioHandles maps devices opened with an AudioSource to their handle.
+var audioHandles = struct {
+	sync.Mutex
+	m map[AudioDeviceID]uintptr
+}{m: make(map[AudioDeviceID]uintptr)}
+
+// OpenAudioDevice opens the named audio device.  An empty device name opens
+// the default device.  OpenAudioDevice returns the device and the spec it was
+// opened with, which may differ from desired in the ways allowedChanges, a
+// combination of AUDIO_ALLOW_* flags, permits.
+//
+// If source is nil the device plays audio queued with QueueAudio, or records
+// audio for DequeueAudio.  Otherwise source is called whenever the device
+// needs audio.
+//
+// Devices start paused, call PauseAudioDevice(dev, false) to start them.  SDL
+// must be initialized with INIT_AUDIO before calling OpenAudioDevice.
+func OpenAudioDevice(device string, iscapture bool, desired *AudioSpec, source AudioSource, allowedChanges int) (AudioDeviceID, *AudioSpec, error) {
+	if desired == nil {
+		return 0, nil, errors.New("OpenAudioDevice: desired spec is nil")
+	}
+
+	var cdevice *C.char
+	if device != "" {
+		cdevice = C.CString(device)
+		defer C.free(unsafe.Pointer(cdevice))
+	}
+	var capture C.int
+	if iscapture {
+		capture = 1
+	}
+
+	var h uintptr
+	var ad *audioDevice
+	if source != nil {
+		ad = &audioDevice{source: source, capture: iscapture}
+		h = newHandle(ad)
+	}
+
+	cdesired := desired.cspec()
+	var cobtained C.SDL_AudioSpec
+	dev := AudioDeviceID(C.openAudioDevice(cdevice, capture, &cdesired,
+		&cobtained, C.int(allowedChanges), C.uintptr_t(h)))
+	if dev == 0 {
+		if h != 0 {
+			deleteHandle(h)
+		}
+		return 0, nil, sdlError(0)
+	}
+
+	obtained := goAudioSpec(&cobtained)
+	if h != 0 {
+		ad.silence = obtained.Silence
+		audioHandles.Lock()
+		audioHandles.m[dev] = h
+		audioHandles.Unlock()
+	}
+	return dev, obtained, nil
+}
+
+//export goAudioCallback
+func goAudioCallback(h C.uintptr_t, stream *C.Uint8, n C.int) {
+	buf := byteSlice(unsafe.Pointer(stream), int(n))
+	ad, _ := handleValue(uintptr(h)).(*audioDevice)
+
+	if ad == nil || !ad.capture {
+		var silence byte
+		if ad != nil {
+			silence = ad.silence
+		}
+		for i := range buf {
+			buf[i] = silence
+		}
+	}
+
+	if ad != nil {
+		ad.source.FillAudio(buf)
+	}
+}
+
+// CloseAudioDevice stops and closes dev.  It waits for a running
+// AudioSource to return, so do not call it while holding a lock the source
+// needs.
+func CloseAudioDevice(dev AudioDeviceID) {
+	C.SDL_CloseAudioDevice(C.SDL_AudioDeviceID(dev))
+
+	audioHandles.Lock()
+	h, ok := audioHandles.m[dev]
+	delete(audioHandles.m, dev)
+	audioHandles.Unlock()
+	if ok {
+		deleteHandle(h)
+	}
+}
+
+// PauseAudioDevice pauses or unpauses dev.  A paused device plays silence.
+func PauseAudioDevice(dev AudioDeviceID, pause bool) {
+	var p C.int
+	if pause {
+		p = 1
+	}
+	C.SDL_PauseAudioDevice(C.SDL_AudioDeviceID(dev), p)
+}
+
+// GetAudioDeviceStatus returns whether dev is stopped, playing, or paused.
+func GetAudioDeviceStatus(dev AudioDeviceID) AudioStatus {
+	return AudioStatus(C.SDL_GetAudioDeviceStatus(C.SDL_AudioDeviceID(dev)))
+}
+
+// LockAudioDevice keeps the AudioSource of dev from running until
+// UnlockAudioDevice is called.
+func LockAudioDevice(dev AudioDeviceID) {
+	C.SDL_LockAudioDevice(C.SDL_AudioDeviceID(dev))
+}
+
+// UnlockAudioDevice lets the AudioSource of dev run again.
+func UnlockAudioDevice(dev AudioDeviceID) {
+	C.SDL_UnlockAudioDevice(C.SDL_AudioDeviceID(dev))
+}
+
+// QueueAudio copies data to the queue of dev, which must have been opened
+// without an AudioSource.  data is in the format of the device.
+func QueueAudio(dev AudioDeviceID, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	r := C.SDL_QueueAudio(C.SDL_AudioDeviceID(dev), unsafe.Pointer(&data[0]),
+		C.Uint32(len(data)))
+	if r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// DequeueAudio moves recorded audio from the capture device dev to data.  It
+// returns the number of bytes moved.
+func DequeueAudio(dev AudioDeviceID, data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	return int(C.SDL_DequeueAudio(C.SDL_AudioDeviceID(dev),
+		unsafe.Pointer(&data[0]), C.Uint32(len(data))))
+}
+
+// GetQueuedAudioSize returns the number of bytes queued on dev.
+func GetQueuedAudioSize(dev AudioDeviceID) uint32 {
+	return uint32(C.SDL_GetQueuedAudioSize(C.SDL_AudioDeviceID(dev)))
+}
+
+// ClearQueuedAudio drops all audio queued on dev.
+func ClearQueuedAudio(dev AudioDeviceID) {
+	C.SDL_ClearQueuedAudio(C.SDL_AudioDeviceID(dev))
+}
+
+// GetNumAudioDrivers returns the number of audio drivers SDL was built with.
+func GetNumAudioDrivers() int {
+	return int(C.SDL_GetNumAudioDrivers())
+}
+
+// GetAudioDriver returns the name of the audio driver at index, for example
+// "pulseaudio", "dummy", or "disk".
+func GetAudioDriver(index int) string {
+	return C.GoString(C.SDL_GetAudioDriver(C.int(index)))
+}
+
+// AudioInit initializes the audio subsystem with the named driver.  Most
+// programs use Init(INIT_AUDIO) instead, which picks the driver from the
+// SDL_AUDIODRIVER environment variable or tries each one in turn.
+func AudioInit(driver string) error {
+	cdriver := C.CString(driver)
+	defer C.free(unsafe.Pointer(cdriver))
+
+	if r := C.SDL_AudioInit(cdriver); r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// AudioQuit shuts down the audio subsystem started with AudioInit.
+func AudioQuit() {
+	C.SDL_AudioQuit()
+}
+
+// GetCurrentAudioDriver returns the name of the audio driver in use, or an
+// empty string if audio is not initialized.
+func GetCurrentAudioDriver() string {
+	return C.GoString(C.SDL_GetCurrentAudioDriver())
+}
+
+// GetNumAudioDevices returns the number of output devices, or capture devices
+// if iscapture is true.  It returns -1 if the number is not known, in which
+// case the default device can still be opened.
+//
+// The list of devices is updated when this function is called, call it again
+// after an AUDIODEVICEADDED or AUDIODEVICEREMOVED event.
+func GetNumAudioDevices(iscapture bool) int {
+	var capture C.int
+	if iscapture {
+		capture = 1
+	}
+	return int(C.SDL_GetNumAudioDevices(capture))
+}
+
+// GetAudioDeviceName returns the name of the device at index, to pass to
+// OpenAudioDevice.  index is from 0 to GetNumAudioDevices(iscapture)-1.
+func GetAudioDeviceName(index int, iscapture bool) string {
+	var capture C.int
+	if iscapture {
+		capture = 1
+	}
+	return C.GoString(C.SDL_GetAudioDeviceName(C.int(index), capture))
+}
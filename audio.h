@@ -0,0 +1,9 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL.h"
+
+extern SDL_AudioDeviceID openAudioDevice(const char *device, int iscapture,
+	SDL_AudioSpec *desired, SDL_AudioSpec *obtained, int allowed_changes,
+	uintptr_t handle);
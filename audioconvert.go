@@ -0,0 +1,171 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+*/
+import "C"
+
+import (
+	"io"
+	"unsafe"
+)
+
+// AudioCVT converts whole buffers of audio from one format, channel count,
+// and rate to another.  Use an AudioStream to convert audio that arrives in
+// pieces.
+type AudioCVT struct {
+	cvt C.SDL_AudioCVT
+
+	// NeedsConversion is false if the source and destination are the same
+	// and Convert only copies.
+	NeedsConversion bool
+}
+
+// BuildAudioCVT prepares an AudioCVT for converting audio from the src
+// format, channels, and rate to the dst format, channels, and rate.
+func BuildAudioCVT(srcFormat AudioFormat, srcChannels uint8, srcRate int,
+	dstFormat AudioFormat, dstChannels uint8, dstRate int) (*AudioCVT, error) {
+	cvt := &AudioCVT{}
+	r := C.SDL_BuildAudioCVT(&cvt.cvt,
+		C.SDL_AudioFormat(srcFormat), C.Uint8(srcChannels), C.int(srcRate),
+		C.SDL_AudioFormat(dstFormat), C.Uint8(dstChannels), C.int(dstRate))
+	if r < 0 {
+		return nil, sdlError(int(r))
+	}
+	cvt.NeedsConversion = r == 1
+	return cvt, nil
+}
+
+// Convert returns src converted to the destination format.  len(src) must be
+// a whole number of sample frames in the source format.
+func (cvt *AudioCVT) Convert(src []byte) ([]byte, error) {
+	if !cvt.NeedsConversion || len(src) == 0 {
+		return append([]byte(nil), src...), nil
+	}
+
+	// SDL converts in place, in a buffer len_mult times the size of src.
+	n := len(src) * int(cvt.cvt.len_mult)
+	buf := C.SDL_malloc(C.size_t(n))
+	if buf == nil {
+		return nil, sdlError(0)
+	}
+	defer C.SDL_free(buf)
+	copy(byteSlice(buf, n), src)
+
+	cvt.cvt.buf = (*C.Uint8)(buf)
+	cvt.cvt.len = C.int(len(src))
+	r := C.SDL_ConvertAudio(&cvt.cvt)
+	cvt.cvt.buf = nil
+	if r != 0 {
+		return nil, sdlError(int(r))
+	}
+	return append([]byte(nil), byteSlice(buf, int(cvt.cvt.len_cvt))...), nil
+}
+
+// AudioStream converts audio from one format, channel count, and rate to
+// another as it arrives.  Put, or Write, adds audio in the source format,
+// and Get, or Read, takes converted audio out.  AudioStream implements
+// io.ReadWriter.
+//
+// For example, to feed 48 kHz float audio to the mixer:
+//
+//  format, freq, channels, _ := mixer.QuerySpec()
+//  s, err := sdl.NewAudioStream(sdl.AUDIO_F32SYS, 2, 48000,
+//      sdl.AudioFormat(format), uint8(channels), freq)
+type AudioStream struct {
+	ptr *C.SDL_AudioStream
+}
+
+// NewAudioStream creates a stream that converts audio from the src format,
+// channels, and rate to the dst format, channels, and rate.  Free the stream
+// when you are done with it.
+func NewAudioStream(srcFormat AudioFormat, srcChannels uint8, srcRate int,
+	dstFormat AudioFormat, dstChannels uint8, dstRate int) (*AudioStream, error) {
+	ptr := C.SDL_NewAudioStream(
+		C.SDL_AudioFormat(srcFormat), C.Uint8(srcChannels), C.int(srcRate),
+		C.SDL_AudioFormat(dstFormat), C.Uint8(dstChannels), C.int(dstRate))
+	if ptr == nil {
+		return nil, sdlError(0)
+	}
+	return &AudioStream{ptr}, nil
+}
+
+// Put adds audio in the source format to s.
+func (s *AudioStream) Put(buf []byte) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	r := C.SDL_AudioStreamPut(s.ptr, unsafe.Pointer(&buf[0]), C.int(len(buf)))
+	if r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// Get moves up to len(buf) bytes of converted audio from s to buf.  It
+// returns the number of bytes moved, which is always a whole number of sample
+// frames.
+func (s *AudioStream) Get(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	r := C.SDL_AudioStreamGet(s.ptr, unsafe.Pointer(&buf[0]), C.int(len(buf)))
+	if r < 0 {
+		return 0, sdlError(int(r))
+	}
+	return int(r), nil
+}
+
+// Available returns the number of converted bytes ready to Get.
+func (s *AudioStream) Available() int {
+	return int(C.SDL_AudioStreamAvailable(s.ptr))
+}
+
+// Flush converts the audio s is holding back for the resampler, so all the
+// audio put in so far can be read.  Call it at the end of the audio.
+func (s *AudioStream) Flush() error {
+	if r := C.SDL_AudioStreamFlush(s.ptr); r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// Clear drops all the audio in s.
+func (s *AudioStream) Clear() {
+	C.SDL_AudioStreamClear(s.ptr)
+}
+
+// Free frees s.
+func (s *AudioStream) Free() {
+	C.SDL_FreeAudioStream(s.ptr)
+	s.ptr = nil
+}
+
+// Write puts p into s.
+func (s *AudioStream) Write(p []byte) (int, error) {
+	if err := s.Put(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Read gets converted audio from s into p.  Like bytes.Buffer, it returns
+// io.EOF when no converted audio is ready, and more can be read after the
+// next Write.
+func (s *AudioStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := s.Get(p)
+	if err != nil {
+		return n, err
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Definitions of the SDL 2.0.9 events, so the package still builds with older
+// headers.  Older libraries never send these events.
+
+#include "SDL.h"
+
+#if !SDL_VERSION_ATLEAST(2, 0, 9)
+
+#define SDL_DISPLAYEVENT 0x150
+#define SDL_SENSORUPDATE 0x1200
+
+#define SDL_DISPLAYEVENT_NONE 0
+#define SDL_DISPLAYEVENT_ORIENTATION 1
+
+typedef struct SDL_DisplayEvent {
+	Uint32 type;
+	Uint32 timestamp;
+	Uint32 display;
+	Uint8 event;
+	Uint8 padding1;
+	Uint8 padding2;
+	Uint8 padding3;
+	Sint32 data1;
+} SDL_DisplayEvent;
+
+typedef struct SDL_SensorEvent {
+	Uint32 type;
+	Uint32 timestamp;
+	Sint32 which;
+	float data[6];
+} SDL_SensorEvent;
+
+#endif
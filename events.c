@@ -0,0 +1,49 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "events.h"
+#include "_cgo_export.h"
+
+// eventThread is the thread that pumps events, recorded once by
+// setEventThread.  SDL runs filters and watches on whichever thread pushes an
+// event, only events pushed on eventThread are passed to Go.
+static void *eventThread;
+
+void setEventThread(void) {
+	SDL_AtomicCASPtr(&eventThread, NULL, (void *)(uintptr_t)SDL_ThreadID());
+}
+
+static int filterEvent(void *userdata, SDL_Event *event) {
+	void *thread = SDL_AtomicGetPtr(&eventThread);
+	if (thread == NULL || (SDL_threadID)(uintptr_t)thread != SDL_ThreadID()) {
+		return 1;
+	}
+	return goEventFilter((uintptr_t)userdata, event);
+}
+
+// runFilter is used by filterEvents, which runs the filter on the calling
+// thread before it returns.
+static int runFilter(void *userdata, SDL_Event *event) {
+	return goEventFilter((uintptr_t)userdata, event);
+}
+
+void setEventFilter(uintptr_t handle) {
+	if (handle == 0) {
+		SDL_SetEventFilter(NULL, NULL);
+		return;
+	}
+	SDL_SetEventFilter(filterEvent, (void *)handle);
+}
+
+void addEventWatch(uintptr_t handle) {
+	SDL_AddEventWatch(filterEvent, (void *)handle);
+}
+
+void delEventWatch(uintptr_t handle) {
+	SDL_DelEventWatch(filterEvent, (void *)handle);
+}
+
+void filterEvents(uintptr_t handle) {
+	SDL_FilterEvents(runFilter, (void *)handle);
+}
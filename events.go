@@ -6,12 +6,15 @@ package sdl
 
 /*
 #include "SDL.h"
+#include "events.h"
 */
 import "C"
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
@@ -122,10 +125,68 @@ func (e *UserEvent) GetType() EventType {
 	return e.Type
 }
 
+// Payload returns the value pushed with PushUserEvent, or nil if e was not
+// pushed with PushUserEvent or its payload has been released with Release.
+func (e *UserEvent) Payload() interface{} {
+	if e.Data2 != payloadMagic {
+		return nil
+	}
+	p, _ := handleValue(e.Data1).(userPayload)
+	return p.v
+}
+
+// Release releases the payload of e, if it was pushed with PushUserEvent.
+// Payload returns nil afterwards, also for copies of e.  It is safe to call
+// Release more than once, and for events without a payload.
+func (e *UserEvent) Release() {
+	if e.Data2 != payloadMagic {
+		return
+	}
+	if _, ok := handleValue(e.Data1).(userPayload); ok {
+		deleteHandle(e.Data1)
+	}
+}
+
 func (e *SysWMEvent) GetType() EventType {
 	return e.Type
 }
 
+func (e *CommonEvent) GetType() EventType {
+	return e.Type
+}
+
+func (e *ClipboardEvent) GetType() EventType {
+	return e.Type
+}
+
+func (e *RenderEvent) GetType() EventType {
+	return e.Type
+}
+
+func (e *DisplayEvent) GetType() EventType {
+	return e.Type
+}
+
+func (e *AudioDeviceEvent) GetType() EventType {
+	return e.Type
+}
+
+// IsCapture returns true if the event is about a capture device.
+func (e *AudioDeviceEvent) IsCapture() bool {
+	return e.Iscapture != 0
+}
+
+// DeviceID returns the device that was removed by an AUDIODEVICEREMOVED
+// event.  For AUDIODEVICEADDED events Which is the index of the new device
+// instead, see GetAudioDeviceName.
+func (e *AudioDeviceEvent) DeviceID() AudioDeviceID {
+	return AudioDeviceID(e.Which)
+}
+
+func (e *SensorEvent) GetType() EventType {
+	return e.Type
+}
+
 func (e *EventUnion) GetType() EventType {
 	return e.Type
 }
@@ -146,6 +207,12 @@ func (event *EventUnion) Convert() Event {
 	switch event.Type {
 	case QUIT:
 		e = (*QuitEvent)(unsafe.Pointer(event))
+	case APP_TERMINATING, APP_LOWMEMORY, APP_WILLENTERBACKGROUND,
+		APP_DIDENTERBACKGROUND, APP_WILLENTERFOREGROUND,
+		APP_DIDENTERFOREGROUND, KEYMAPCHANGED:
+		e = (*CommonEvent)(unsafe.Pointer(event))
+	case DISPLAYEVENT:
+		e = (*DisplayEvent)(unsafe.Pointer(event))
 	case WINDOWEVENT:
 		e = (*WindowEvent)(unsafe.Pointer(event))
 	case SYSWMEVENT:
@@ -182,10 +249,18 @@ func (event *EventUnion) Convert() Event {
 		e = (*TouchFingerEvent)(unsafe.Pointer(event))
 	case MULTIGESTURE:
 		e = (*MultiGestureEvent)(unsafe.Pointer(event))
-	case DOLLARGESTURE:
+	case DOLLARGESTURE, DOLLARRECORD:
 		e = (*DollarGestureEvent)(unsafe.Pointer(event))
-	case DROPFILE:
+	case CLIPBOARDUPDATE:
+		e = (*ClipboardEvent)(unsafe.Pointer(event))
+	case DROPFILE, DROPTEXT, DROPBEGIN, DROPCOMPLETE:
 		e = (*DropEvent)(unsafe.Pointer(event))
+	case AUDIODEVICEADDED, AUDIODEVICEREMOVED:
+		e = (*AudioDeviceEvent)(unsafe.Pointer(event))
+	case SENSORUPDATE:
+		e = (*SensorEvent)(unsafe.Pointer(event))
+	case RENDER_TARGETS_RESET, RENDER_DEVICE_RESET:
+		e = (*RenderEvent)(unsafe.Pointer(event))
 	default:
 		e = (*UserEvent)(unsafe.Pointer(event))
 	}
@@ -274,6 +349,27 @@ func CopyEventToEventUnion(ev Event, evu *EventUnion) error {
 	case *UserEvent:
 		sh.Data = uintptr(unsafe.Pointer(t))
 		sh.Len = int(unsafe.Sizeof(*t))
+	case *CommonEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *ClipboardEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *RenderEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *DisplayEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *AudioDeviceEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *SensorEvent:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
+	case *EventUnion:
+		sh.Data = uintptr(unsafe.Pointer(t))
+		sh.Len = int(unsafe.Sizeof(*t))
 	default:
 		return fmt.Errorf("Unknown type: %T", t)
 	}
@@ -295,13 +391,23 @@ const (
 
 	QUIT EventType = C.SDL_QUIT
 
+	APP_TERMINATING         EventType = C.SDL_APP_TERMINATING
+	APP_LOWMEMORY           EventType = C.SDL_APP_LOWMEMORY
+	APP_WILLENTERBACKGROUND EventType = C.SDL_APP_WILLENTERBACKGROUND
+	APP_DIDENTERBACKGROUND  EventType = C.SDL_APP_DIDENTERBACKGROUND
+	APP_WILLENTERFOREGROUND EventType = C.SDL_APP_WILLENTERFOREGROUND
+	APP_DIDENTERFOREGROUND  EventType = C.SDL_APP_DIDENTERFOREGROUND
+
+	DISPLAYEVENT EventType = C.SDL_DISPLAYEVENT
+
 	WINDOWEVENT EventType = C.SDL_WINDOWEVENT
 	SYSWMEVENT  EventType = C.SDL_SYSWMEVENT
 
-	KEYDOWN     EventType = C.SDL_KEYDOWN
-	KEYUP       EventType = C.SDL_KEYUP
-	TEXTEDITING EventType = C.SDL_TEXTEDITING
-	TEXTINPUT   EventType = C.SDL_TEXTINPUT
+	KEYDOWN       EventType = C.SDL_KEYDOWN
+	KEYUP         EventType = C.SDL_KEYUP
+	TEXTEDITING   EventType = C.SDL_TEXTEDITING
+	TEXTINPUT     EventType = C.SDL_TEXTINPUT
+	KEYMAPCHANGED EventType = C.SDL_KEYMAPCHANGED
 
 	MOUSEMOTION     EventType = C.SDL_MOUSEMOTION
 	MOUSEBUTTONDOWN EventType = C.SDL_MOUSEBUTTONDOWN
@@ -333,7 +439,18 @@ const (
 
 	CLIPBOARDUPDATE EventType = C.SDL_CLIPBOARDUPDATE
 
-	DROPFILE EventType = C.SDL_DROPFILE
+	DROPFILE     EventType = C.SDL_DROPFILE
+	DROPTEXT     EventType = C.SDL_DROPTEXT
+	DROPBEGIN    EventType = C.SDL_DROPBEGIN
+	DROPCOMPLETE EventType = C.SDL_DROPCOMPLETE
+
+	AUDIODEVICEADDED   EventType = C.SDL_AUDIODEVICEADDED
+	AUDIODEVICEREMOVED EventType = C.SDL_AUDIODEVICEREMOVED
+
+	SENSORUPDATE EventType = C.SDL_SENSORUPDATE
+
+	RENDER_TARGETS_RESET EventType = C.SDL_RENDER_TARGETS_RESET
+	RENDER_DEVICE_RESET  EventType = C.SDL_RENDER_DEVICE_RESET
 
 	USEREVENT EventType = C.SDL_USEREVENT
 
@@ -343,12 +460,20 @@ const (
 var eventTypeStrings = map[EventType]string{
 	FIRSTEVENT:               "FIRSTEVENT",
 	QUIT:                     "QUIT",
+	APP_TERMINATING:          "APP_TERMINATING",
+	APP_LOWMEMORY:            "APP_LOWMEMORY",
+	APP_WILLENTERBACKGROUND:  "APP_WILLENTERBACKGROUND",
+	APP_DIDENTERBACKGROUND:   "APP_DIDENTERBACKGROUND",
+	APP_WILLENTERFOREGROUND:  "APP_WILLENTERFOREGROUND",
+	APP_DIDENTERFOREGROUND:   "APP_DIDENTERFOREGROUND",
+	DISPLAYEVENT:             "DISPLAYEVENT",
 	WINDOWEVENT:              "WINDOWEVENT",
 	SYSWMEVENT:               "SYSWMEVENT",
 	KEYDOWN:                  "KEYDOWN",
 	KEYUP:                    "KEYUP",
 	TEXTEDITING:              "TEXTEDITING",
 	TEXTINPUT:                "TEXTINPUT",
+	KEYMAPCHANGED:            "KEYMAPCHANGED",
 	MOUSEMOTION:              "MOUSEMOTION",
 	MOUSEBUTTONDOWN:          "MOUSEBUTTONDOWN",
 	MOUSEBUTTONUP:            "MOUSEBUTTONUP",
@@ -374,6 +499,14 @@ var eventTypeStrings = map[EventType]string{
 	MULTIGESTURE:             "MULTIGESTURE",
 	CLIPBOARDUPDATE:          "CLIPBOARDUPDATE",
 	DROPFILE:                 "DROPFILE",
+	DROPTEXT:                 "DROPTEXT",
+	DROPBEGIN:                "DROPBEGIN",
+	DROPCOMPLETE:             "DROPCOMPLETE",
+	AUDIODEVICEADDED:         "AUDIODEVICEADDED",
+	AUDIODEVICEREMOVED:       "AUDIODEVICEREMOVED",
+	SENSORUPDATE:             "SENSORUPDATE",
+	RENDER_TARGETS_RESET:     "RENDER_TARGETS_RESET",
+	RENDER_DEVICE_RESET:      "RENDER_DEVICE_RESET",
 	USEREVENT:                "USEREVENT",
 	LASTEVENT:                "LASTEVENT",
 }
@@ -394,6 +527,20 @@ func validEventType(t EventType) bool {
 	switch {
 	case t == QUIT:
 		return true
+	case t == APP_TERMINATING:
+		return true
+	case t == APP_LOWMEMORY:
+		return true
+	case t == APP_WILLENTERBACKGROUND:
+		return true
+	case t == APP_DIDENTERBACKGROUND:
+		return true
+	case t == APP_WILLENTERFOREGROUND:
+		return true
+	case t == APP_DIDENTERFOREGROUND:
+		return true
+	case t == DISPLAYEVENT:
+		return true
 	case t == WINDOWEVENT:
 		return true
 	case t == SYSWMEVENT:
@@ -406,6 +553,8 @@ func validEventType(t EventType) bool {
 		return true
 	case t == TEXTINPUT:
 		return true
+	case t == KEYMAPCHANGED:
+		return true
 	case t == MOUSEMOTION:
 		return true
 	case t == MOUSEBUTTONDOWN:
@@ -449,13 +598,29 @@ func validEventType(t EventType) bool {
 	case t == DOLLARGESTURE:
 		return true
 	case t == DOLLARRECORD:
-		return false
+		return true
 	case t == MULTIGESTURE:
 		return true
 	case t == CLIPBOARDUPDATE:
-		return false
+		return true
 	case t == DROPFILE:
 		return true
+	case t == DROPTEXT:
+		return true
+	case t == DROPBEGIN:
+		return true
+	case t == DROPCOMPLETE:
+		return true
+	case t == AUDIODEVICEADDED:
+		return true
+	case t == AUDIODEVICEREMOVED:
+		return true
+	case t == SENSORUPDATE:
+		return true
+	case t == RENDER_TARGETS_RESET:
+		return true
+	case t == RENDER_DEVICE_RESET:
+		return true
 	case t >= USEREVENT && t <= LASTEVENT:
 		return true
 	}
@@ -467,7 +632,8 @@ func validEventType(t EventType) bool {
 //
 // On windows this will need to be run in each OS thread that creates a
 // windows.  For this reason you will likely want to call
-// runtime.LockOSThread() before creating a window.
+// runtime.LockOSThread() before creating a window, or use Main and Do to run
+// all of your window, render, and event calls on the main thread.
 func PumpEvents() {
 	C.SDL_PumpEvents()
 }
@@ -527,16 +693,45 @@ func HasEvents(minType, maxType EventType) bool {
 	return false
 }
 
-// FlushEvent clears events of type typ from the event queue.
+// FlushEvent clears events of type typ from the event queue.  Payloads of
+// events pushed with PushUserEvent are released.
 func FlushEvent(typ EventType) {
+	releasePayloads(typ, typ)
 	C.SDL_FlushEvent(C.Uint32(typ))
 }
 
-// FlushEvents clears events between minType and maxType from the event queue.
+// FlustEvents clears events between minType and maxType from the event queue.
+// Payloads of events pushed with PushUserEvent are released.
 func FlustEvents(minType, maxType EventType) {
+	releasePayloads(minType, maxType)
 	C.SDL_FlushEvents(C.Uint32(minType), C.Uint32(maxType))
 }
 
+// releasePayloads removes the user events between minType and maxType from
+// the event queue and releases their payloads.
+func releasePayloads(minType, maxType EventType) {
+	if maxType < USEREVENT {
+		return
+	}
+	if minType < USEREVENT {
+		minType = USEREVENT
+	}
+
+	events := make([]EventUnion, 16)
+	for {
+		n, err := PeepEvents(events, GETEVENT, minType, maxType)
+		if err != nil {
+			return
+		}
+		for i := 0; i < n; i++ {
+			(*UserEvent)(unsafe.Pointer(&events[i])).Release()
+		}
+		if n < len(events) {
+			return
+		}
+	}
+}
+
 // PollEvent polls for currently pending events.  It returns 1 if there are
 // any pending events, or 0 if there are none available.  If event is nil
 // the next event will not be removed from the queue.
@@ -544,6 +739,58 @@ func PollEvent(event *EventUnion) int {
 	return int(C.SDL_PollEvent((*C.SDL_Event)(unsafe.Pointer(event))))
 }
 
+// PollEventTyped polls for currently pending events.  It returns the next
+// event converted to its concrete type, such as *KeyboardEvent or
+// *WindowEvent, or nil if there are no pending events.
+//
+// Each call returns a new event, so events can be kept and calls can be
+// made from several goroutines.  Use PollEventInto to poll without
+// allocating.
+func PollEventTyped() Event {
+	return PollEventInto(new(EventUnion))
+}
+
+// PollEventInto is the same as PollEventTyped, but reads the event into buf
+// instead of allocating a new one.  The returned event points into buf, so it
+// is overwritten by the next call with the same buf.  Copy the event to keep
+// it, and do not share buf between goroutines.
+func PollEventInto(buf *EventUnion) Event {
+	if PollEvent(buf) == 0 {
+		return nil
+	}
+	return buf.Convert()
+}
+
+// WaitEventTyped is the same as PollEventTyped, but waits indefinitely for
+// the next available event.
+func WaitEventTyped() (Event, error) {
+	return WaitEventInto(new(EventUnion))
+}
+
+// WaitEventInto is the same as WaitEventTyped, but reads the event into buf
+// like PollEventInto.
+func WaitEventInto(buf *EventUnion) (Event, error) {
+	if err := WaitEvent(buf); err != nil {
+		return nil, err
+	}
+	return buf.Convert(), nil
+}
+
+// WaitEventTimeoutTyped is the same as PollEventTyped, but waits until the
+// specified timeout (in milliseconds) for the next available event.
+func WaitEventTimeoutTyped(timeout int) (Event, error) {
+	return WaitEventTimeoutInto(new(EventUnion), timeout)
+}
+
+// WaitEventTimeoutInto is the same as WaitEventTimeoutTyped, but reads the
+// event into buf like PollEventInto.
+func WaitEventTimeoutInto(buf *EventUnion, timeout int) (Event, error) {
+	if err := WaitEventTimeout(buf, timeout); err != nil {
+		return nil, err
+	}
+	return buf.Convert(), nil
+}
+
 // WaitEvent waits indefinitely for the next available event.  If event is nil
 // the next event will not be removed from the queue.
 func WaitEvent(event *EventUnion) error {
@@ -568,6 +815,134 @@ func WaitEventTimeout(event *EventUnion, timeout int) error {
 	return nil
 }
 
+// wakeEvent is the event type pushed to wake up WaitEventContext when its
+// context is done.
+var wakeEvent struct {
+	once sync.Once
+	typ  EventType
+}
+
+func wakeEventType() EventType {
+	wakeEvent.once.Do(func() {
+		wakeEvent.typ = RegisterEvents(1)
+	})
+	return wakeEvent.typ
+}
+
+// WaitEventContext waits for the next available event like WaitEvent, but
+// returns ctx.Err() as soon as ctx is done.  Use a context with a deadline to
+// wait with a timeout.  If event is nil the next event will not be removed
+// from the queue.
+//
+// WaitEventContext is woken up by pushing an event of a type allocated with
+// RegisterEvents.  That event is never returned and is flushed from the
+// queue before WaitEventContext returns.
+func WaitEventContext(ctx context.Context, event *EventUnion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	wake := wakeEventType()
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			PushEvent(&EventUnion{Type: wake})
+		case <-stop:
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-done
+		FlushEvent(wake)
+	}()
+
+	peek := make([]EventUnion, 1)
+	for {
+		if err := WaitEvent(nil); err != nil {
+			return err
+		}
+		if _, err := PeepEvents(peek, PEEKEVENT, FIRSTEVENT, LASTEVENT); err != nil {
+			return err
+		}
+		if peek[0].Type != wake {
+			break
+		}
+
+		FlushEvent(wake)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if event != nil {
+		PollEvent(event)
+	}
+	return nil
+}
+
+// eventsWaitTimeout is how long, in milliseconds, Events waits for an event
+// on the main thread before letting other calls made with Do run.
+const eventsWaitTimeout = 10
+
+// Events pumps events on the main thread and delivers them, converted to
+// their concrete types, on the returned channel.  Each event is a copy that
+// stays valid after it has been received.  The channel is closed when ctx is
+// done, pending events that have not been received are dropped and their
+// payloads released.
+//
+// Events uses Do, so Main must be running.  While waiting for events it
+// holds the main thread for at most 10 milliseconds at a time, so other calls
+// made with Do are delayed by no more than that.
+func Events(ctx context.Context) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+
+		var batch []Event
+		for ctx.Err() == nil {
+			Do(func() {
+				batch = pollEvents(batch[:0])
+			})
+			for i, e := range batch {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					for _, e := range batch[i:] {
+						if u, ok := e.(*UserEvent); ok {
+							u.Release()
+						}
+					}
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// pollEvents waits for an event for up to eventsWaitTimeout milliseconds
+// and appends copies of all the pending events to batch.
+func pollEvents(batch []Event) []Event {
+	var buf EventUnion
+	if C.SDL_WaitEventTimeout(nil, eventsWaitTimeout) == 0 {
+		return batch
+	}
+
+	wake := wakeEventType()
+	for PollEvent(&buf) != 0 {
+		if buf.Type == wake {
+			continue
+		}
+		ev := new(EventUnion)
+		*ev = buf
+		batch = append(batch, ev.Convert())
+	}
+	return batch
+}
+
 // PushEvent adds an event to the event queue. It returns 1 on succes or 0 if
 // the event was filtered.
 func PushEvent(event *EventUnion) (int, error) {
@@ -579,18 +954,153 @@ func PushEvent(event *EventUnion) (int, error) {
 	return r, nil
 }
 
-/*
-Skipping
-SDL_SetEventFilter()
-SDL_GetEventFilter()
-The comment for SetEventFilter says the function can run in another thread,
-as far as I know this would cause the program to crash as go would not know
-about the thread.
-SDL_AddEventWatch()
-SDL_DelEventWatch()
-SDL_FilterEvents()
-I don't know if these can run in a different thread like SetEventFilter can.
-*/
+// payloadMagic is stored in Data2 of events pushed with PushUserEvent.
+const payloadMagic = 0x53444c50
+
+// userPayload wraps the payloads of events pushed with PushUserEvent, so
+// Payload can tell them apart from other handles.
+type userPayload struct {
+	v interface{}
+}
+
+// PushUserEvent adds a UserEvent of type typ to the event queue, carrying
+// code and payload.  payload can be any Go value, read it back with Payload.
+// typ should be allocated with RegisterEvents.  Data1 and Data2 of the pushed
+// event are used to find the payload and must not be changed.
+//
+// The payload stays alive until it is released.  Call Release on the event
+// once its payload has been read, however the event was read from the queue.
+// Events dropped by Events, or flushed with FlushEvent or FlustEvents, are
+// released for you.  An event that is never released keeps its payload, and
+// whatever it refers to, forever.
+//
+// PushUserEvent can be called from any goroutine.  If the event is dropped
+// by a filter its payload is released right away.
+func PushUserEvent(typ EventType, code int32, payload interface{}) error {
+	h := newHandle(userPayload{payload})
+
+	ev := UserEvent{
+		Type:  typ,
+		Code:  code,
+		Data1: h,
+		Data2: payloadMagic,
+	}
+	var evu EventUnion
+	if err := CopyEventToEventUnion(&ev, &evu); err != nil {
+		deleteHandle(h)
+		return err
+	}
+
+	r, err := PushEvent(&evu)
+	if r != 1 {
+		deleteHandle(h)
+	}
+	return err
+}
+
+// EventFilter is a function that is called for events.  Returning false
+// from a filter drops the event, returning true keeps it.  The return value
+// of a watch is ignored.
+//
+// event points to SDL's copy of the event, changing its fields rewrites the
+// event.  It is only valid until the filter returns.  If a filter drops a
+// DropEvent it must call FreeFile.
+//
+// SDL runs filters on whichever thread pushes an event, which is not always
+// a thread that can safely run Go code: the audio thread, for example, pushes
+// events with the audio device locked.  Go filters and watches are therefore
+// only run for events pushed on the event thread, the thread that pumps
+// events.  It is the thread running Main, or else the first thread to call
+// Init, InitSubSystem, SetEventFilter, or AddEventWatch, and it never changes
+// afterwards.
+//
+// Events pushed from other threads skip Go filters and watches.  This
+// includes events from AddTimer and the mixer hooks, and events pushed with
+// PushEvent or PushUserEvent from a goroutine that is not on the event
+// thread.  Push them inside Do to have them filtered.
+type EventFilter func(event Event) bool
+
+// EventWatch identifies a watch added with AddEventWatch.
+type EventWatch uintptr
+
+// eventFilter is the handle of the filter set with SetEventFilter.
+var eventFilter = struct {
+	sync.Mutex
+	h uintptr
+}{}
+
+// SetEventFilter sets up a filter to process all events before they are
+// added to the event queue.  A nil filter removes the current filter.
+//
+// The filter is also run for events added with PushEvent, but not for events
+// added with PeepEvents.  See EventFilter for which thread it runs on.
+func SetEventFilter(filter EventFilter) {
+	eventFilter.Lock()
+	defer eventFilter.Unlock()
+
+	old := eventFilter.h
+	eventFilter.h = 0
+	if filter != nil {
+		eventFilter.h = newHandle(filter)
+	}
+	setEventThread()
+	C.setEventFilter(C.uintptr_t(eventFilter.h))
+	deleteHandle(old)
+}
+
+// GetEventFilter returns the filter set with SetEventFilter, or nil if there
+// is no filter.
+func GetEventFilter() EventFilter {
+	eventFilter.Lock()
+	defer eventFilter.Unlock()
+
+	f, _ := handleValue(eventFilter.h).(EventFilter)
+	return f
+}
+
+// AddEventWatch adds a function that is called when an event is added to the
+// event queue, after it has passed the filter set with SetEventFilter.  Use
+// a watch to react to events as they happen, for example to redraw while a
+// window is being resized.  See EventFilter for which thread it runs on.
+func AddEventWatch(watch EventFilter) EventWatch {
+	setEventThread()
+	h := newHandle(watch)
+	C.addEventWatch(C.uintptr_t(h))
+	return EventWatch(h)
+}
+
+// DelEventWatch removes a watch added with AddEventWatch.
+func DelEventWatch(w EventWatch) {
+	C.delEventWatch(C.uintptr_t(w))
+	deleteHandle(uintptr(w))
+}
+
+// FilterEvents runs filter on every event in the event queue and removes the
+// events for which it returns false.  filter is run on the current thread
+// before FilterEvents returns.
+//
+// Note: filter must not add events to the queue, the queue is locked while
+// it runs.
+func FilterEvents(filter EventFilter) {
+	h := newHandle(filter)
+	defer deleteHandle(h)
+	C.filterEvents(C.uintptr_t(h))
+}
+
+// setEventThread records the current thread as the event thread, unless it
+// has already been recorded.  See EventFilter.
+func setEventThread() {
+	C.setEventThread()
+}
+
+//export goEventFilter
+func goEventFilter(h C.uintptr_t, event *C.SDL_Event) C.int {
+	filter, _ := handleValue(uintptr(h)).(EventFilter)
+	if filter == nil || filter((*EventUnion)(unsafe.Pointer(event)).Convert()) {
+		return 1
+	}
+	return 0
+}
 
 const (
 	QUERY   = C.SDL_QUERY
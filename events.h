@@ -0,0 +1,12 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL.h"
+#include "compat.h"
+
+extern void setEventThread(void);
+extern void setEventFilter(uintptr_t handle);
+extern void addEventWatch(uintptr_t handle);
+extern void delEventWatch(uintptr_t handle);
+extern void filterEvents(uintptr_t handle);
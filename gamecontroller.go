@@ -66,6 +66,34 @@ func GameControllerAddMapping(mappingString string) error {
 	return nil
 }
 
+// GameControllerAddMappingsFromRW loads a set of mappings, one per line, from
+// rw, like the gamecontrollerdb.txt file.  Mappings for other platforms are
+// skipped.  If freerw is true rw is closed after it has been read, even if
+// there is an error.  It returns the number of mappings added.
+func GameControllerAddMappingsFromRW(rw *RWops, freerw bool) (int, error) {
+	var f C.int
+	if freerw {
+		f = 1
+	}
+
+	r := int(C.SDL_GameControllerAddMappingsFromRW(
+		(*C.SDL_RWops)(unsafe.Pointer(rw)), f))
+	if r == -1 {
+		return 0, sdlError(r)
+	}
+	return r, nil
+}
+
+// GameControllerAddMappingsFromFile loads a set of mappings from a file.  See
+// GameControllerAddMappingsFromRW.
+func GameControllerAddMappingsFromFile(file string) (int, error) {
+	rw, err := RWFromFile(file, "rb")
+	if err != nil {
+		return 0, err
+	}
+	return GameControllerAddMappingsFromRW(rw, true)
+}
+
 // GameControllerMapping returns a mapping string for guid.
 func (guid JoystickGUID) GameControllerMapping() (string, error) {
 	cstr := C.SDL_GameControllerMappingForGUID(
@@ -0,0 +1,44 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+import "sync"
+
+// handles lets C code hold on to Go values.  C may not keep Go pointers, so
+// it is given an integer handle instead, which is looked up when C calls
+// back into Go.  Handles are never reused.
+var handles = struct {
+	sync.Mutex
+	m    map[uintptr]interface{}
+	next uintptr
+}{m: make(map[uintptr]interface{})}
+
+// newHandle stores v and returns a non-zero handle for it.
+func newHandle(v interface{}) uintptr {
+	handles.Lock()
+	defer handles.Unlock()
+
+	handles.next++
+	h := handles.next
+	handles.m[h] = v
+	return h
+}
+
+// handleValue returns the value stored for h, or nil if h is not a valid
+// handle.
+func handleValue(h uintptr) interface{} {
+	handles.Lock()
+	defer handles.Unlock()
+
+	return handles.m[h]
+}
+
+// deleteHandle releases h.  It is safe to delete a handle more than once.
+func deleteHandle(h uintptr) {
+	handles.Lock()
+	defer handles.Unlock()
+
+	delete(handles.m, h)
+}
@@ -0,0 +1,222 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"grate/backend/sdl2"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Device is the kind of input a Binding reads.
+type Device int
+
+const (
+	Scancode         Device = iota + 1 // A physical key
+	Keycode                            // A key in the current keyboard layout
+	MouseButton                        // A mouse button, BUTTON_LEFT and so on
+	ControllerButton                   // A game controller button
+	ControllerAxis                     // A game controller axis
+)
+
+var deviceNames = map[Device]string{
+	Scancode:         "scancode",
+	Keycode:          "key",
+	MouseButton:      "mouse",
+	ControllerButton: "button",
+	ControllerAxis:   "axis",
+}
+
+var mouseNames = map[uint32]string{
+	sdl.BUTTON_LEFT:   "left",
+	sdl.BUTTON_MIDDLE: "middle",
+	sdl.BUTTON_RIGHT:  "right",
+	sdl.BUTTON_X1:     "x1",
+	sdl.BUTTON_X2:     "x2",
+}
+
+func (d Device) String() string {
+	if s, ok := deviceNames[d]; ok {
+		return s
+	}
+	return "Device(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Binding binds one key, button, or axis to an action.
+//
+// Direction is the sign of the value the binding gives an action.  A key or
+// button with Direction -1 gives -1 while it is held, so two keys can drive
+// one axis.  An axis with Direction 1 or -1 only uses that half of the axis,
+// an axis with Direction 0 uses the whole axis.
+//
+// Axis values within DeadZone of the center are treated as centered.
+// DeadZone is a fraction of the full range, from 0 to 1.
+type Binding struct {
+	Device    Device
+	Code      int32 // The Scancode, Keycode, mouse button, ControllerButton, or ControllerAxis
+	Direction int8
+	DeadZone  float32
+}
+
+// Key returns a binding for a physical key.
+func Key(code sdl.Scancode) Binding {
+	return Binding{Device: Scancode, Code: int32(code)}
+}
+
+// Sym returns a binding for a key in the current keyboard layout.
+func Sym(key sdl.Keycode) Binding {
+	return Binding{Device: Keycode, Code: int32(key)}
+}
+
+// Mouse returns a binding for a mouse button, for example sdl.BUTTON_LEFT.
+func Mouse(button uint32) Binding {
+	return Binding{Device: MouseButton, Code: int32(button)}
+}
+
+// Button returns a binding for a game controller button.
+func Button(button sdl.ControllerButton) Binding {
+	return Binding{Device: ControllerButton, Code: int32(button)}
+}
+
+// Axis returns a binding for a game controller axis.  direction is 1 or -1
+// to bind half the axis, or 0 to bind the whole axis.
+func Axis(axis sdl.ControllerAxis, direction int8, deadZone float32) Binding {
+	return Binding{Device: ControllerAxis, Code: int32(axis),
+		Direction: direction, DeadZone: deadZone}
+}
+
+// Negative returns b with its Direction set to -1.
+func (b Binding) Negative() Binding {
+	b.Direction = -1
+	return b
+}
+
+// String returns the text form of b, see MarshalText.
+func (b Binding) String() string {
+	t, err := b.MarshalText()
+	if err != nil {
+		return "invalid binding: " + err.Error()
+	}
+	return string(t)
+}
+
+// MarshalText encodes b as text, so bindings can be saved with encoding/json
+// or any other encoder that uses encoding.TextMarshaler.  The text is the
+// device, a colon, and the SDL name of the key, button, or axis, for example
+// "scancode:Space", "key:Return", "mouse:left", "button:a", or "axis:leftx".
+// A leading "-" or "+" is the Direction, and "@" and a number after an axis
+// is its DeadZone, for example "-axis:lefty@0.2".
+//
+// Key names come from GetScancodeName and GetKeyName, controller names from
+// GameControllerGetStringForButton and GameControllerGetStringForAxis, so the
+// names match the ones SDL uses everywhere else.
+func (b Binding) MarshalText() ([]byte, error) {
+	var name string
+	switch b.Device {
+	case Scancode:
+		name = sdl.GetScancodeName(sdl.Scancode(b.Code))
+	case Keycode:
+		name = sdl.GetKeyName(sdl.Keycode(b.Code))
+	case MouseButton:
+		name = mouseNames[uint32(b.Code)]
+	case ControllerButton:
+		name = sdl.GameControllerGetStringForButton(sdl.ControllerButton(b.Code))
+	case ControllerAxis:
+		name = sdl.GameControllerGetStringForAxis(sdl.ControllerAxis(b.Code))
+	default:
+		return nil, fmt.Errorf("input: unknown device %v", b.Device)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("input: %v %d has no name", b.Device, b.Code)
+	}
+
+	s := b.Device.String() + ":" + name
+	switch {
+	case b.Direction < 0:
+		s = "-" + s
+	case b.Direction > 0 && b.Device == ControllerAxis:
+		s = "+" + s
+	}
+	if b.Device == ControllerAxis && b.DeadZone != 0 {
+		s += "@" + strconv.FormatFloat(float64(b.DeadZone), 'g', -1, 32)
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText decodes text written by MarshalText into b.
+func (b *Binding) UnmarshalText(text []byte) error {
+	s := string(text)
+	var n Binding
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		n.Direction = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		n.Direction = 1
+		s = s[1:]
+	}
+
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return fmt.Errorf("input: invalid binding %q", text)
+	}
+	device, name := s[:i], s[i+1:]
+	for d, dn := range deviceNames {
+		if dn == device {
+			n.Device = d
+		}
+	}
+
+	switch n.Device {
+	case Scancode:
+		code := sdl.GetScancodeFromName(name)
+		if code == sdl.SCANCODE_UNKNOWN {
+			return fmt.Errorf("input: unknown scancode %q", name)
+		}
+		n.Code = int32(code)
+	case Keycode:
+		key := sdl.GetKeyFromName(name)
+		if key == sdl.K_UNKNOWN {
+			return fmt.Errorf("input: unknown key %q", name)
+		}
+		n.Code = int32(key)
+	case MouseButton:
+		for button, bn := range mouseNames {
+			if bn == name {
+				n.Code = int32(button)
+			}
+		}
+		if n.Code == 0 {
+			return fmt.Errorf("input: unknown mouse button %q", name)
+		}
+	case ControllerButton:
+		button := sdl.GameControllerGetButtonFromString(name)
+		if button == sdl.CONTROLLER_BUTTON_INVALID {
+			return fmt.Errorf("input: unknown controller button %q", name)
+		}
+		n.Code = int32(button)
+	case ControllerAxis:
+		if i := strings.LastIndexByte(name, '@'); i >= 0 {
+			dz, err := strconv.ParseFloat(name[i+1:], 32)
+			if err != nil || dz < 0 || dz >= 1 {
+				return fmt.Errorf("input: invalid dead zone in %q", text)
+			}
+			n.DeadZone = float32(dz)
+			name = name[:i]
+		}
+		axis := sdl.GameControllerGetAxisFromString(name)
+		if axis == sdl.CONTROLLER_AXIS_INVALID {
+			return fmt.Errorf("input: unknown controller axis %q", name)
+		}
+		n.Code = int32(axis)
+	default:
+		return fmt.Errorf("input: unknown device %q", device)
+	}
+
+	*b = n
+	return nil
+}
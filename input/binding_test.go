@@ -0,0 +1,77 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"grate/backend/sdl2"
+	"testing"
+)
+
+func TestBindingText(t *testing.T) {
+	tests := []struct {
+		b    Binding
+		text string
+	}{
+		{Key(sdl.SCANCODE_SPACE), "scancode:Space"},
+		{Key(sdl.SCANCODE_A).Negative(), "-scancode:A"},
+		{Sym(sdl.K_RETURN), "key:Return"},
+		{Mouse(sdl.BUTTON_LEFT), "mouse:left"},
+		{Mouse(sdl.BUTTON_X2), "mouse:x2"},
+		{Button(sdl.CONTROLLER_BUTTON_A), "button:a"},
+		{Axis(sdl.CONTROLLER_AXIS_LEFTX, 0, 0), "axis:leftx"},
+		{Axis(sdl.CONTROLLER_AXIS_LEFTX, 0, 0.25), "axis:leftx@0.25"},
+		{Axis(sdl.CONTROLLER_AXIS_RIGHTY, -1, 0.2), "-axis:righty@0.2"},
+		{Axis(sdl.CONTROLLER_AXIS_RIGHTY, 1, 0), "+axis:righty"},
+	}
+	for _, test := range tests {
+		text, err := test.b.MarshalText()
+		if err != nil {
+			t.Errorf("%#v: %v", test.b, err)
+			continue
+		}
+		if string(text) != test.text {
+			t.Errorf("%#v: got %q, want %q", test.b, text, test.text)
+		}
+
+		var b Binding
+		if err := b.UnmarshalText(text); err != nil {
+			t.Errorf("%q: %v", text, err)
+			continue
+		}
+		if b != test.b {
+			t.Errorf("%q: got %#v, want %#v", text, b, test.b)
+		}
+	}
+}
+
+func TestBindingTextInvalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"-",
+		"scancode",
+		"joystick:0",
+		"scancode:NoSuchKey",
+		"key:NoSuchKey",
+		"mouse:thumb",
+		"button:nope",
+		"axis:nope",
+		"axis:leftx@",
+		"axis:leftx@x",
+		"axis:leftx@1",
+		"axis:leftx@-0.1",
+	} {
+		var b Binding
+		if err := b.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("%q: got %#v, want an error", text, b)
+		}
+	}
+
+	if _, err := (Binding{}).MarshalText(); err == nil {
+		t.Error("zero Binding: got no error from MarshalText")
+	}
+	if _, err := Mouse(42).MarshalText(); err == nil {
+		t.Error("unknown mouse button: got no error from MarshalText")
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package input maps keys, mouse buttons, and game controllers to named
+// actions, so a game asks whether "jump" was pressed instead of whether the
+// space bar or the A button was.
+//
+// Usage
+//
+// Bind actions once, then feed an sdl.InputState every frame:
+//
+//  state := sdl.NewInputState()
+//  m := input.NewMap(state)
+//  m.Bind("jump", input.Key(sdl.SCANCODE_SPACE), input.Button(sdl.CONTROLLER_BUTTON_A))
+//  m.Bind("move_x", input.Key(sdl.SCANCODE_A).Negative(), input.Key(sdl.SCANCODE_D),
+//      input.Axis(sdl.CONTROLLER_AXIS_LEFTX, 0, 0.2))
+//
+//  for running {
+//      state.NextFrame()
+//      for e := sdl.PollEventTyped(); e != nil; e = sdl.PollEventTyped() {
+//          state.HandleEvent(e)
+//      }
+//      if m.Pressed("jump") {
+//          ...
+//      }
+//      x := m.Value("move_x")
+//      ...
+//  }
+//
+// Bindings implements encoding.TextMarshaler through Binding, so it can be
+// saved and loaded with encoding/json or a TOML library.  ReadTOML and
+// WriteTOML read and write bindings files without one.
+//
+// Binding names come from SDL, so SDL must be initialized with the video
+// subsystem before Keycode bindings are marshaled or unmarshaled.
+package input
+
+import (
+	"grate/backend/sdl2"
+	"sort"
+)
+
+// AnyController makes a Map read all connected game controllers.
+const AnyController sdl.JoystickID = -1
+
+// Bindings maps action names to the inputs bound to them.
+type Bindings map[string][]Binding
+
+// Actions returns the names of the actions in b, sorted.
+func (b Bindings) Actions() []string {
+	names := make([]string, 0, len(b))
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Map reads actions from an sdl.InputState.
+type Map struct {
+	// Controller is the instance id of the game controller the map reads,
+	// or AnyController.  Give each local player their own Map to read
+	// their own controller.
+	Controller sdl.JoystickID
+
+	state    *sdl.InputState
+	bindings Bindings
+}
+
+// NewMap returns a Map with no bindings that reads state and all game
+// controllers.
+func NewMap(state *sdl.InputState) *Map {
+	return &Map{Controller: AnyController, state: state, bindings: make(Bindings)}
+}
+
+// Bind adds bindings to action.
+func (m *Map) Bind(action string, bindings ...Binding) {
+	m.bindings[action] = append(m.bindings[action], bindings...)
+}
+
+// Unbind removes all bindings from action.
+func (m *Map) Unbind(action string) {
+	delete(m.bindings, action)
+}
+
+// Bindings returns a copy of the bindings of m, for saving.
+func (m *Map) Bindings() Bindings {
+	b := make(Bindings, len(m.bindings))
+	for action, list := range m.bindings {
+		b[action] = append([]Binding(nil), list...)
+	}
+	return b
+}
+
+// SetBindings replaces the bindings of m with a copy of b.
+func (m *Map) SetBindings(b Bindings) {
+	m.bindings = make(Bindings, len(b))
+	for action, list := range b {
+		m.bindings[action] = append([]Binding(nil), list...)
+	}
+}
+
+// Pressed returns true if any input bound to action went down during the
+// frame.  An axis goes down when it leaves its dead zone.
+func (m *Map) Pressed(action string) bool {
+	for _, b := range m.bindings[action] {
+		if m.pressed(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Released returns true if an input bound to action went up during the frame
+// and no input bound to action is held.
+func (m *Map) Released(action string) bool {
+	released := false
+	for _, b := range m.bindings[action] {
+		if m.held(b) {
+			return false
+		}
+		released = released || m.released(b)
+	}
+	return released
+}
+
+// Held returns true if any input bound to action is down.
+func (m *Map) Held(action string) bool {
+	for _, b := range m.bindings[action] {
+		if m.held(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the sum of the values of the inputs bound to action, clamped
+// to the range -1 to 1.  A held key or button has the value of its
+// Direction, 1 if Direction is 0.  An axis has its position outside the dead
+// zone, scaled so it still reaches 1 at the end of the axis.
+func (m *Map) Value(action string) float32 {
+	var v float32
+	for _, b := range m.bindings[action] {
+		if b.Device == ControllerAxis {
+			cur, _ := m.axis(b)
+			v += cur
+		} else if m.held(b) {
+			v += sign(b)
+		}
+	}
+
+	if v > 1 {
+		return 1
+	}
+	if v < -1 {
+		return -1
+	}
+	return v
+}
+
+func sign(b Binding) float32 {
+	if b.Direction < 0 {
+		return -1
+	}
+	return 1
+}
+
+func (m *Map) controllers() []sdl.JoystickID {
+	if m.Controller != AnyController {
+		return []sdl.JoystickID{m.Controller}
+	}
+	return m.state.Controllers()
+}
+
+func (m *Map) pressed(b Binding) bool {
+	switch b.Device {
+	case Scancode:
+		return m.state.KeyPressed(sdl.Scancode(b.Code))
+	case Keycode:
+		return m.state.KeyPressed(sdl.GetScancodeFromKey(sdl.Keycode(b.Code)))
+	case MouseButton:
+		return m.state.MouseButtonPressed(uint32(b.Code))
+	case ControllerButton:
+		for _, id := range m.controllers() {
+			if m.state.ControllerButtonPressed(id, sdl.ControllerButton(b.Code)) {
+				return true
+			}
+		}
+	case ControllerAxis:
+		cur, prev := m.axis(b)
+		return cur != 0 && prev == 0
+	}
+	return false
+}
+
+func (m *Map) released(b Binding) bool {
+	switch b.Device {
+	case Scancode:
+		return m.state.KeyReleased(sdl.Scancode(b.Code))
+	case Keycode:
+		return m.state.KeyReleased(sdl.GetScancodeFromKey(sdl.Keycode(b.Code)))
+	case MouseButton:
+		return m.state.MouseButtonReleased(uint32(b.Code))
+	case ControllerButton:
+		for _, id := range m.controllers() {
+			if m.state.ControllerButtonReleased(id, sdl.ControllerButton(b.Code)) {
+				return true
+			}
+		}
+	case ControllerAxis:
+		cur, prev := m.axis(b)
+		return cur == 0 && prev != 0
+	}
+	return false
+}
+
+func (m *Map) held(b Binding) bool {
+	switch b.Device {
+	case Scancode:
+		return m.state.KeyHeld(sdl.Scancode(b.Code))
+	case Keycode:
+		return m.state.KeyHeld(sdl.GetScancodeFromKey(sdl.Keycode(b.Code)))
+	case MouseButton:
+		return m.state.MouseButtonHeld(uint32(b.Code))
+	case ControllerButton:
+		for _, id := range m.controllers() {
+			if m.state.ControllerButtonHeld(id, sdl.ControllerButton(b.Code)) {
+				return true
+			}
+		}
+	case ControllerAxis:
+		cur, _ := m.axis(b)
+		return cur != 0
+	}
+	return false
+}
+
+// axis returns the value of an axis binding now and at the start of the
+// frame.  With several controllers the one pushed furthest wins.
+func (m *Map) axis(b Binding) (cur, prev float32) {
+	axis := sdl.ControllerAxis(b.Code)
+	for _, id := range m.controllers() {
+		v := m.state.ControllerAxis(id, axis)
+		p := int32(v) - m.state.ControllerAxisDelta(id, axis)
+		if c := axisValue(b, int32(v)); abs(c) > abs(cur) {
+			cur = c
+		}
+		if c := axisValue(b, p); abs(c) > abs(prev) {
+			prev = c
+		}
+	}
+	return cur, prev
+}
+
+func axisValue(b Binding, raw int32) float32 {
+	v := float32(raw) / 32767
+	if v < -1 {
+		v = -1
+	}
+	if b.Direction > 0 && v < 0 || b.Direction < 0 && v > 0 {
+		return 0
+	}
+
+	a := abs(v)
+	if a <= b.DeadZone {
+		return 0
+	}
+	a = (a - b.DeadZone) / (1 - b.DeadZone)
+	if v < 0 {
+		return -a
+	}
+	return a
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,253 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteTOML writes b to w as a TOML document with one key per action:
+//
+//  jump = ["scancode:Space", "button:a"]
+//  move_x = ["-scancode:A", "scancode:D", "axis:leftx@0.2"]
+func (b Bindings) WriteTOML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, action := range b.Actions() {
+		bw.WriteString(tomlKey(action))
+		bw.WriteString(" = [")
+		for i, binding := range b[action] {
+			t, err := binding.MarshalText()
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				bw.WriteString(", ")
+			}
+			bw.WriteString(tomlString(string(t)))
+		}
+		bw.WriteString("]\n")
+	}
+	return bw.Flush()
+}
+
+// ReadTOML reads bindings written by WriteTOML.  It understands the part of
+// TOML that a bindings file needs: comments, bare and quoted keys, and
+// strings or arrays of strings as values.  Tables are not supported.  An
+// action with an empty array is kept with no bindings, and an action that
+// appears twice is an error.
+func ReadTOML(r io.Reader) (Bindings, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := tomlParser{s: string(data), line: 1}
+	b := make(Bindings)
+	for {
+		p.skip()
+		if p.eof() {
+			return b, nil
+		}
+
+		action, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := b[action]; ok {
+			return nil, p.errorf("duplicate action %q", action)
+		}
+		p.skip()
+		if !p.accept('=') {
+			return nil, p.errorf("expected '=' after %q", action)
+		}
+
+		values, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]Binding, 0, len(values))
+		for _, v := range values {
+			var binding Binding
+			if err := binding.UnmarshalText([]byte(v)); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			list = append(list, binding)
+		}
+		b[action] = list
+	}
+}
+
+func tomlKey(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if !isBareKey(r) {
+			return tomlString(s)
+		}
+	}
+	return s
+}
+
+func isBareKey(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '-'
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+type tomlParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("input: toml line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+// skip skips white space, new lines, and comments.
+func (p *tomlParser) skip() {
+	for !p.eof() {
+		switch c := p.s[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) accept(c byte) bool {
+	if !p.eof() && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tomlParser) key() (string, error) {
+	if !p.eof() && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		return p.str()
+	}
+	if !p.eof() && p.s[p.pos] == '[' {
+		return "", p.errorf("tables are not supported")
+	}
+
+	start := p.pos
+	for !p.eof() {
+		r, n := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !isBareKey(r) {
+			break
+		}
+		p.pos += n
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a key")
+	}
+	return p.s[start:p.pos], nil
+}
+
+// value parses a string or an array of strings.
+func (p *tomlParser) value() ([]string, error) {
+	p.skip()
+	if !p.accept('[') {
+		s, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+
+	var values []string
+	for {
+		p.skip()
+		if p.accept(']') {
+			return values, nil
+		}
+		s, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, s)
+
+		p.skip()
+		if p.accept(']') {
+			return values, nil
+		}
+		if !p.accept(',') {
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// str parses a basic string in double quotes or a literal string in single
+// quotes.
+func (p *tomlParser) str() (string, error) {
+	if p.accept('\'') {
+		end := strings.IndexAny(p.s[p.pos:], "'\n")
+		if end < 0 || p.s[p.pos+end] != '\'' {
+			return "", p.errorf("unterminated string")
+		}
+		s := p.s[p.pos : p.pos+end]
+		p.pos += end + 1
+		return s, nil
+	}
+
+	if p.eof() || p.s[p.pos] != '"' {
+		return "", p.errorf("expected a string")
+	}
+	start := p.pos
+	for p.pos++; !p.eof(); p.pos++ {
+		switch p.s[p.pos] {
+		case '\\':
+			p.pos++
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(p.s[start:p.pos])
+			if err != nil {
+				return "", p.errorf("invalid string %s", p.s[start:p.pos])
+			}
+			return s, nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
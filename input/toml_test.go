@@ -0,0 +1,96 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"grate/backend/sdl2"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTOMLRoundTrip(t *testing.T) {
+	b := Bindings{
+		"jump":        {Key(sdl.SCANCODE_SPACE), Button(sdl.CONTROLLER_BUTTON_A)},
+		"move_x":      {Key(sdl.SCANCODE_A).Negative(), Key(sdl.SCANCODE_D), Axis(sdl.CONTROLLER_AXIS_LEFTX, 0, 0.2)},
+		"menu select": {Sym(sdl.K_RETURN), Mouse(sdl.BUTTON_LEFT)},
+		"unbound":     {},
+	}
+
+	var buf bytes.Buffer
+	if err := b.WriteTOML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `jump = ["scancode:Space", "button:a"]
+"menu select" = ["key:Return", "mouse:left"]
+move_x = ["-scancode:A", "scancode:D", "axis:leftx@0.2"]
+unbound = []
+`
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	got, err := ReadTOML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("got %v, want %v", got, b)
+	}
+}
+
+func TestReadTOML(t *testing.T) {
+	doc := `# Controls
+jump = "scancode:Space"   # a single string
+fire = [
+	'mouse:left',         # literal strings
+	"button:rightshoulder",
+]
+"quoted \"key\"" = []
+`
+	got, err := ReadTOML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Bindings{
+		"jump":         {Key(sdl.SCANCODE_SPACE)},
+		"fire":         {Mouse(sdl.BUTTON_LEFT), Button(sdl.CONTROLLER_BUTTON_RIGHTSHOULDER)},
+		`quoted "key"`: {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadTOMLInvalid(t *testing.T) {
+	tests := []struct {
+		doc  string
+		line string
+	}{
+		{"jump", "line 1:"},
+		{"jump \"scancode:Space\"", "line 1:"},
+		{"= \"scancode:Space\"", "line 1:"},
+		{"[controls]\njump = []", "line 1:"},
+		{"\n\njump = \"scancode:Space", "line 3:"},
+		{"jump = 'scancode:Space\n'", "line 1:"},
+		{"jump = \"scancode:\\q\"", "line 1:"},
+		{"jump = [\"scancode:Space\" \"button:a\"]", "line 1:"},
+		{"jump = [\"scancode:Space\",", "line 1:"},
+		{"jump = scancode:Space", "line 1:"},
+		{"# comment\njump = [\"scancode:NoSuchKey\"]", "line 2:"},
+		{"jump = []\nfire = []\njump = [\"button:a\"]", "line 3:"},
+	}
+	for _, test := range tests {
+		_, err := ReadTOML(strings.NewReader(test.doc))
+		if err == nil {
+			t.Errorf("%q: got no error", test.doc)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.line) {
+			t.Errorf("%q: got %q, want an error on %s", test.doc, err, test.line)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+// Flags kept for every key and button tracked by InputState.
+const (
+	inputHeld     = 1 << iota // Down at the end of the frame
+	inputPressed              // Went down during the frame
+	inputReleased             // Went up during the frame
+)
+
+// InputState tracks the keyboard, the mouse, and every game controller from
+// the events of one frame, so it can tell when a key or button went down or
+// up during the frame instead of only whether it is down now.
+//
+// Feed it every event with HandleEvent and call NextFrame once at the start of
+// each frame, before the events of the frame are handled:
+//
+//  input.NextFrame()
+//  for e := sdl.PollEventTyped(); e != nil; e = sdl.PollEventTyped() {
+//  	input.HandleEvent(e)
+//  }
+//  if input.KeyPressed(sdl.SCANCODE_SPACE) {
+//  	jump()
+//  }
+//
+// A key or button that is pressed and released within one frame reports both
+// Pressed and Released, but not Held.  Key repeats are ignored.
+//
+// Controllers are identified by their joystick instance id, the Which field
+// of controller events.  A controller is tracked from its first button or
+// axis event until it is removed, so it is safe to plug and unplug
+// controllers at any time.
+type InputState struct {
+	keys [NUM_SCANCODES]uint8
+
+	mouseHeld     uint32
+	mousePressed  uint32
+	mouseReleased uint32
+	mouseX        int32
+	mouseY        int32
+	mouseDX       int32
+	mouseDY       int32
+	wheelX        int32
+	wheelY        int32
+
+	controllers map[JoystickID]*controllerState
+}
+
+type controllerState struct {
+	buttons  [CONTROLLER_BUTTON_MAX]uint8
+	axes     [CONTROLLER_AXIS_MAX]int16
+	prevAxes [CONTROLLER_AXIS_MAX]int16
+}
+
+// NewInputState returns an InputState with nothing held.
+func NewInputState() *InputState {
+	return &InputState{controllers: make(map[JoystickID]*controllerState)}
+}
+
+// NextFrame starts a new frame.  It forgets which keys and buttons were
+// pressed or released, and resets the mouse motion, the wheel totals, and the
+// axis deltas.
+func (s *InputState) NextFrame() {
+	for i := range s.keys {
+		s.keys[i] &= inputHeld
+	}
+
+	s.mousePressed = 0
+	s.mouseReleased = 0
+	s.mouseDX, s.mouseDY = 0, 0
+	s.wheelX, s.wheelY = 0, 0
+
+	for _, c := range s.controllers {
+		for i := range c.buttons {
+			c.buttons[i] &= inputHeld
+		}
+		c.prevAxes = c.axes
+	}
+}
+
+// HandleEvent updates s from event.  Events that are not keyboard, mouse, or
+// game controller events are ignored.
+func (s *InputState) HandleEvent(event Event) {
+	if u, ok := event.(*EventUnion); ok {
+		event = u.Convert()
+	}
+
+	switch e := event.(type) {
+	case *KeyboardEvent:
+		if e.Repeat != 0 || e.Keysym.Scancode >= NUM_SCANCODES {
+			return
+		}
+		s.keys[e.Keysym.Scancode] = updateInput(s.keys[e.Keysym.Scancode],
+			e.State == PRESSED)
+	case *MouseMotionEvent:
+		s.mouseX, s.mouseY = e.X, e.Y
+		s.mouseDX += e.Xrel
+		s.mouseDY += e.Yrel
+	case *MouseButtonEvent:
+		s.mouseX, s.mouseY = e.X, e.Y
+		mask := Button(uint32(e.Button))
+		if e.State == PRESSED {
+			s.mouseHeld |= mask
+			s.mousePressed |= mask
+		} else {
+			s.mouseHeld &^= mask
+			s.mouseReleased |= mask
+		}
+	case *MouseWheelEvent:
+		s.wheelX += e.X
+		s.wheelY += e.Y
+	case *ControllerButtonEvent:
+		c := s.controller(JoystickID(e.Which))
+		if ControllerButton(e.Button) >= CONTROLLER_BUTTON_MAX {
+			return
+		}
+		c.buttons[e.Button] = updateInput(c.buttons[e.Button],
+			e.State == PRESSED)
+	case *ControllerAxisEvent:
+		c := s.controller(JoystickID(e.Which))
+		if ControllerAxis(e.Axis) >= CONTROLLER_AXIS_MAX {
+			return
+		}
+		c.axes[e.Axis] = e.Value
+	case *ControllerDeviceEvent:
+		if e.Type == CONTROLLERDEVICEREMOVED {
+			delete(s.controllers, JoystickID(e.Which))
+		}
+	}
+}
+
+func updateInput(flags uint8, down bool) uint8 {
+	if down {
+		if flags&inputHeld == 0 {
+			flags |= inputHeld | inputPressed
+		}
+	} else if flags&inputHeld != 0 {
+		flags = flags&^inputHeld | inputReleased
+	}
+	return flags
+}
+
+func (s *InputState) controller(id JoystickID) *controllerState {
+	if s.controllers == nil {
+		s.controllers = make(map[JoystickID]*controllerState)
+	}
+	c, ok := s.controllers[id]
+	if !ok {
+		c = &controllerState{}
+		s.controllers[id] = c
+	}
+	return c
+}
+
+func (s *InputState) key(code Scancode) uint8 {
+	if code >= NUM_SCANCODES {
+		return 0
+	}
+	return s.keys[code]
+}
+
+// KeyPressed returns true if the key went down during the frame.
+func (s *InputState) KeyPressed(code Scancode) bool {
+	return s.key(code)&inputPressed != 0
+}
+
+// KeyReleased returns true if the key went up during the frame.
+func (s *InputState) KeyReleased(code Scancode) bool {
+	return s.key(code)&inputReleased != 0
+}
+
+// KeyHeld returns true if the key is down.
+func (s *InputState) KeyHeld(code Scancode) bool {
+	return s.key(code)&inputHeld != 0
+}
+
+// MouseButtonPressed returns true if the mouse button, for example
+// BUTTON_LEFT, went down during the frame.
+func (s *InputState) MouseButtonPressed(button uint32) bool {
+	return s.mousePressed&Button(button) != 0
+}
+
+// MouseButtonReleased returns true if the mouse button went up during the
+// frame.
+func (s *InputState) MouseButtonReleased(button uint32) bool {
+	return s.mouseReleased&Button(button) != 0
+}
+
+// MouseButtonHeld returns true if the mouse button is down.
+func (s *InputState) MouseButtonHeld(button uint32) bool {
+	return s.mouseHeld&Button(button) != 0
+}
+
+// MousePosition returns the last known mouse position, relative to the window
+// with mouse focus.
+func (s *InputState) MousePosition() (x, y int32) {
+	return s.mouseX, s.mouseY
+}
+
+// MouseDelta returns how far the mouse moved during the frame.
+func (s *InputState) MouseDelta() (dx, dy int32) {
+	return s.mouseDX, s.mouseDY
+}
+
+// Wheel returns how far the mouse wheel scrolled during the frame.
+func (s *InputState) Wheel() (x, y int32) {
+	return s.wheelX, s.wheelY
+}
+
+func (s *InputState) button(id JoystickID, button ControllerButton) uint8 {
+	c, ok := s.controllers[id]
+	if !ok || button < 0 || button >= CONTROLLER_BUTTON_MAX {
+		return 0
+	}
+	return c.buttons[button]
+}
+
+// ControllerButtonPressed returns true if the button of controller id went
+// down during the frame.
+func (s *InputState) ControllerButtonPressed(id JoystickID, button ControllerButton) bool {
+	return s.button(id, button)&inputPressed != 0
+}
+
+// ControllerButtonReleased returns true if the button of controller id went
+// up during the frame.
+func (s *InputState) ControllerButtonReleased(id JoystickID, button ControllerButton) bool {
+	return s.button(id, button)&inputReleased != 0
+}
+
+// ControllerButtonHeld returns true if the button of controller id is down.
+func (s *InputState) ControllerButtonHeld(id JoystickID, button ControllerButton) bool {
+	return s.button(id, button)&inputHeld != 0
+}
+
+// ControllerAxis returns the value of the axis of controller id, from -32768
+// to 32767.  It returns 0 for unknown controllers.
+func (s *InputState) ControllerAxis(id JoystickID, axis ControllerAxis) int16 {
+	c, ok := s.controllers[id]
+	if !ok || axis < 0 || axis >= CONTROLLER_AXIS_MAX {
+		return 0
+	}
+	return c.axes[axis]
+}
+
+// ControllerAxisDelta returns how far the axis of controller id moved during
+// the frame.
+func (s *InputState) ControllerAxisDelta(id JoystickID, axis ControllerAxis) int32 {
+	c, ok := s.controllers[id]
+	if !ok || axis < 0 || axis >= CONTROLLER_AXIS_MAX {
+		return 0
+	}
+	return int32(c.axes[axis]) - int32(c.prevAxes[axis])
+}
+
+// Controllers returns the instance ids of the controllers s is tracking.
+func (s *InputState) Controllers() []JoystickID {
+	ids := make([]JoystickID, 0, len(s.controllers))
+	for id := range s.controllers {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+import "testing"
+
+func keyEvent(state uint8, repeat uint8) *KeyboardEvent {
+	e := &KeyboardEvent{Type: KEYDOWN, State: state, Repeat: repeat}
+	if state == RELEASED {
+		e.Type = KEYUP
+	}
+	e.Keysym.Scancode = SCANCODE_SPACE
+	return e
+}
+
+func TestInputStateKeys(t *testing.T) {
+	s := NewInputState()
+
+	s.HandleEvent(keyEvent(PRESSED, 0))
+	if !s.KeyPressed(SCANCODE_SPACE) || !s.KeyHeld(SCANCODE_SPACE) {
+		t.Errorf("key not pressed after KEYDOWN")
+	}
+
+	s.NextFrame()
+	s.HandleEvent(keyEvent(PRESSED, 1))
+	if s.KeyPressed(SCANCODE_SPACE) || !s.KeyHeld(SCANCODE_SPACE) {
+		t.Errorf("key repeat reported as a press")
+	}
+
+	s.NextFrame()
+	s.HandleEvent(keyEvent(RELEASED, 0))
+	s.HandleEvent(keyEvent(PRESSED, 0))
+	s.HandleEvent(keyEvent(RELEASED, 0))
+	if !s.KeyPressed(SCANCODE_SPACE) || !s.KeyReleased(SCANCODE_SPACE) ||
+		s.KeyHeld(SCANCODE_SPACE) {
+		t.Errorf("tap within one frame not reported as press and release")
+	}
+
+	s.NextFrame()
+	if s.KeyPressed(SCANCODE_SPACE) || s.KeyReleased(SCANCODE_SPACE) {
+		t.Errorf("edges not reset by NextFrame")
+	}
+}
+
+func TestInputStateControllers(t *testing.T) {
+	s := NewInputState()
+
+	s.HandleEvent(&ControllerButtonEvent{Type: CONTROLLERBUTTONDOWN, Which: 3,
+		Button: uint8(CONTROLLER_BUTTON_A), State: PRESSED})
+	s.HandleEvent(&ControllerAxisEvent{Type: CONTROLLERAXISMOTION, Which: 3,
+		Axis: uint8(CONTROLLER_AXIS_LEFTX), Value: 1000})
+	if !s.ControllerButtonPressed(3, CONTROLLER_BUTTON_A) {
+		t.Errorf("controller button not pressed")
+	}
+	if d := s.ControllerAxisDelta(3, CONTROLLER_AXIS_LEFTX); d != 1000 {
+		t.Errorf("axis delta = %d, want 1000", d)
+	}
+
+	s.NextFrame()
+	if d := s.ControllerAxisDelta(3, CONTROLLER_AXIS_LEFTX); d != 0 {
+		t.Errorf("axis delta = %d after NextFrame, want 0", d)
+	}
+
+	s.HandleEvent(&ControllerDeviceEvent{Type: CONTROLLERDEVICEREMOVED, Which: 3})
+	if len(s.Controllers()) != 0 || s.ControllerButtonHeld(3, CONTROLLER_BUTTON_A) {
+		t.Errorf("controller still tracked after removal")
+	}
+}
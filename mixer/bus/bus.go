@@ -0,0 +1,294 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bus sorts mixer channels into named categories, such as "sfx", "ui",
+// and "voice", each with its own volume and mute, and ducks the music while
+// a category plays.
+//
+// Usage
+//
+// Create the buses once after mixer.OpenAudio, play sounds through them, and
+// call Update once per frame:
+//
+//  buses := bus.New()
+//  sfx, _ := buses.Add("sfx", 16)
+//  voice, _ := buses.Add("voice", 2)
+//  buses.DuckMusic(voice, 0.3, 250)
+//  ...
+//  sfx.Play(explosion, 0)
+//  ...
+//  buses.Get("sfx").SetVolume(settings.SFXVolume)
+//  buses.Update()
+//
+// Each bus owns a range of channels, tagged as a mixer group and reserved so
+// that mixer.PlayChannel(-1, ...) does not pick them.  The bus sets their
+// volume with mixer.SetChannelVolume, so a volume set on a single sound with
+// mixer.Voice.SetVolume scales the volume of the bus instead of replacing it.
+package bus
+
+import (
+	"grate/backend/sdl2"
+	"grate/backend/sdl2/mixer"
+	"errors"
+)
+
+const maxVolume = 128
+
+// Buses is a set of buses and the music volume.
+type Buses struct {
+	buses  map[string]*Bus
+	next   int // First channel not owned by a bus
+	master float64
+	muted  bool
+
+	music     float64
+	musicGain float64 // Set by SetMusicGain
+	duck      *Bus
+	duckLevel float64
+	duckMs    uint32
+	duckGain  float64 // Current ducking gain, 1 when not ducked
+	lastTick  uint32
+}
+
+// Bus is a category of sounds that play on their own channels.
+type Bus struct {
+	buses  *Buses
+	name   string
+	tag    int
+	first  int
+	last   int
+	volume float64
+	muted  bool
+}
+
+// New returns an empty set of buses.  Buses start at full volume.
+func New() *Buses {
+	return &Buses{
+		buses:     make(map[string]*Bus),
+		master:    1,
+		music:     1,
+		musicGain: 1,
+		duckGain:  1,
+	}
+}
+
+// Add adds a bus named name with channels channels of its own, allocating
+// more mixer channels if needed.
+func (bs *Buses) Add(name string, channels int) (*Bus, error) {
+	if _, ok := bs.buses[name]; ok {
+		return nil, errors.New("bus: " + name + " already exists")
+	}
+	if channels <= 0 {
+		return nil, errors.New("bus: a bus needs at least one channel")
+	}
+
+	first := bs.next
+	last := first + channels - 1
+	if mixer.AllocateChannels(-1) <= last {
+		mixer.AllocateChannels(last + 1)
+	}
+	mixer.ReserveChannels(last + 1)
+
+	b := &Bus{buses: bs, name: name, tag: len(bs.buses) + 1,
+		first: first, last: last, volume: 1}
+	if mixer.GroupChannels(first, last, b.tag) != channels {
+		return nil, errors.New("bus: could not group channels")
+	}
+	bs.next = last + 1
+	bs.buses[name] = b
+	b.apply()
+	return b, nil
+}
+
+// Get returns the bus named name, or nil.
+func (bs *Buses) Get(name string) *Bus {
+	return bs.buses[name]
+}
+
+// SetMaster sets the volume of all buses and the music, from 0 to 1.
+func (bs *Buses) SetMaster(volume float64) {
+	bs.master = clamp(volume)
+	bs.applyAll()
+}
+
+// SetMasterMute mutes or unmutes all buses and the music.
+func (bs *Buses) SetMasterMute(mute bool) {
+	bs.muted = mute
+	bs.applyAll()
+}
+
+// SetMusicVolume sets the volume of the music, from 0 to 1.
+func (bs *Buses) SetMusicVolume(volume float64) {
+	bs.music = clamp(volume)
+	bs.applyMusic()
+}
+
+// SetMusicGain sets a second music volume, from 0 to 1, that multiplies the
+// one set with SetMusicVolume.  It is meant for whatever plays the music,
+// such as package playlist, to set the volume of each track without undoing
+// the music volume and ducking of the buses.
+func (bs *Buses) SetMusicGain(gain float64) {
+	bs.musicGain = clamp(gain)
+	bs.applyMusic()
+}
+
+// DuckMusic lowers the music to level, from 0 to 1, while a sound plays on
+// trigger, fading over ms milliseconds.  A nil trigger turns ducking off.
+// Ducking happens in Update.
+func (bs *Buses) DuckMusic(trigger *Bus, level float64, ms uint32) {
+	bs.duck = trigger
+	bs.duckLevel = clamp(level)
+	bs.duckMs = ms
+	if trigger == nil {
+		bs.duckGain = 1
+		bs.applyMusic()
+	}
+}
+
+// Update fades the music towards its ducked or normal volume.  Call it once
+// per frame.
+func (bs *Buses) Update() {
+	now := sdl.GetTicks()
+	elapsed := now - bs.lastTick
+	if bs.lastTick == 0 {
+		elapsed = 0
+	}
+	bs.lastTick = now
+
+	if bs.duck == nil {
+		return
+	}
+	target := 1.0
+	if bs.duck.Playing() > 0 {
+		target = bs.duckLevel
+	}
+	if bs.duckGain == target {
+		return
+	}
+
+	step := 1.0
+	if bs.duckMs > 0 {
+		step = float64(elapsed) / float64(bs.duckMs)
+	}
+	if bs.duckGain < target {
+		bs.duckGain += step
+		if bs.duckGain > target {
+			bs.duckGain = target
+		}
+	} else {
+		bs.duckGain -= step
+		if bs.duckGain < target {
+			bs.duckGain = target
+		}
+	}
+	bs.applyMusic()
+}
+
+func (bs *Buses) applyAll() {
+	for _, b := range bs.buses {
+		b.apply()
+	}
+	bs.applyMusic()
+}
+
+func (bs *Buses) gain() float64 {
+	if bs.muted {
+		return 0
+	}
+	return bs.master
+}
+
+func (bs *Buses) applyMusic() {
+	mixer.VolumeMusic(int(bs.gain() * bs.music * bs.musicGain * bs.duckGain * maxVolume))
+}
+
+// Name returns the name of b.
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// Tag returns the mixer group tag of the channels of b.
+func (b *Bus) Tag() int {
+	return b.tag
+}
+
+// Play plays chunk on a free channel of b.  If every channel of b is busy the
+// sound that has played the longest is stopped to make room.
+func (b *Bus) Play(chunk mixer.Chunk, loops int) (mixer.Voice, error) {
+	return b.PlayTimed(chunk, loops, -1)
+}
+
+// PlayTimed is the same as Play, but the sound plays at most ticks
+// milliseconds.
+func (b *Bus) PlayTimed(chunk mixer.Chunk, loops, ticks int) (mixer.Voice, error) {
+	ch := mixer.GroupAvailable(b.tag)
+	if ch == -1 {
+		ch = mixer.GroupOldest(b.tag)
+	}
+	if ch == -1 {
+		ch = b.first
+	}
+	return mixer.PlayVoiceTimed(ch, chunk, loops, ticks)
+}
+
+// Playing returns the number of channels of b that are playing.
+func (b *Bus) Playing() int {
+	n := 0
+	for ch := b.first; ch <= b.last; ch++ {
+		n += mixer.Playing(ch)
+	}
+	return n
+}
+
+// SetVolume sets the volume of b, from 0 to 1.
+func (b *Bus) SetVolume(volume float64) {
+	b.volume = clamp(volume)
+	b.apply()
+}
+
+// Volume returns the volume of b.
+func (b *Bus) Volume() float64 {
+	return b.volume
+}
+
+// SetMute mutes or unmutes b.  The volume of b is kept while it is muted.
+func (b *Bus) SetMute(mute bool) {
+	b.muted = mute
+	b.apply()
+}
+
+// Muted returns true if b is muted.
+func (b *Bus) Muted() bool {
+	return b.muted
+}
+
+// FadeOut fades out all sounds on b over ms milliseconds.
+func (b *Bus) FadeOut(ms int) {
+	mixer.FadeOutGroup(b.tag, ms)
+}
+
+// Halt stops all sounds on b.
+func (b *Bus) Halt() {
+	mixer.HaltGroup(b.tag)
+}
+
+func (b *Bus) apply() {
+	v := b.volume * b.buses.gain()
+	if b.muted {
+		v = 0
+	}
+	for ch := b.first; ch <= b.last; ch++ {
+		mixer.SetChannelVolume(ch, int(v*maxVolume))
+	}
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
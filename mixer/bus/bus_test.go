@@ -0,0 +1,69 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bus
+
+import (
+	"grate/backend/sdl2"
+	"grate/backend/sdl2/mixer"
+	"os"
+	"testing"
+)
+
+func TestVoiceVolume(t *testing.T) {
+	os.Setenv("SDL_AUDIODRIVER", "dummy")
+	if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+		t.Skip(err)
+	}
+	defer sdl.Quit()
+	if err := mixer.OpenAudio(44100, mixer.AUDIO_S16SYS, 2, 1024); err != nil {
+		t.Skip(err)
+	}
+	defer mixer.CloseAudio()
+
+	chunk, err := mixer.QuickLoad_RAW(make([]byte, 4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chunk.Free()
+
+	buses := New()
+	sfx, err := buses.Add("sfx", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := sfx.Play(chunk, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetVolume(64)
+
+	check := func(what string, voice mixer.Voice, want int) {
+		t.Helper()
+		if got := mixer.Volume(voice.Channel(), -1); got != want {
+			t.Errorf("%s: got volume %d, want %d", what, got, want)
+		}
+	}
+	check("voice volume", v, 64)
+	sfx.SetVolume(0.5)
+	check("bus volume", v, 32)
+	buses.SetMaster(0.5)
+	check("master volume", v, 16)
+	sfx.SetMute(true)
+	check("muted", v, 0)
+	sfx.SetMute(false)
+	check("unmuted", v, 16)
+
+	// A new sound on a muted bus stays muted, it does not get the volume
+	// of an earlier voice.
+	v.Stop()
+	sfx.SetMute(true)
+	w, err := sfx.Play(chunk, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("new voice on muted bus", w, 0)
+	sfx.SetMute(false)
+	check("new voice", w, 32)
+}
@@ -0,0 +1,167 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "SDL2/SDL_mixer.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// chunkMem maps the chunks created by QuickLoad_WAV, QuickLoad_RAW, and
+// NewChunkFromSamples to the C memory holding their samples.  The mixer does
+// not free that memory itself, so Chunk.Free does.
+var chunkMem = struct {
+	sync.Mutex
+	m map[*C.Mix_Chunk]unsafe.Pointer
+}{m: make(map[*C.Mix_Chunk]unsafe.Pointer)}
+
+// takeChunkMem removes c from chunkMem and returns its memory, or nil if the
+// mixer allocated it.  Chunk.Free takes the memory before freeing c, so a
+// chunk created afterwards at the same address can not lose its memory.
+func takeChunkMem(c *C.Mix_Chunk) unsafe.Pointer {
+	chunkMem.Lock()
+	defer chunkMem.Unlock()
+
+	mem := chunkMem.m[c]
+	delete(chunkMem.m, c)
+	return mem
+}
+
+// cCopy copies b to memory allocated with SDL_malloc.
+func cCopy(b []byte) (unsafe.Pointer, error) {
+	mem := C.SDL_malloc(C.size_t(len(b)))
+	if mem == nil {
+		return nil, errors.New("mixer: out of memory")
+	}
+	copy(byteSlice(mem, len(b)), b)
+	return mem, nil
+}
+
+// checkQuickWAV returns an error unless Mix_QuickLoad_WAV can read mem
+// without running past its end.  Mix_QuickLoad_WAV trusts the header: it
+// skips the RIFF header and walks the chunks of the file until it finds the
+// data chunk, which it takes at its word.
+func checkQuickWAV(mem []byte) error {
+	if len(mem) < 44 || string(mem[0:4]) != "RIFF" || string(mem[8:12]) != "WAVE" {
+		return errors.New("mixer: not a WAV file")
+	}
+	fmtFound := false
+	for pos := 12; pos+8 <= len(mem); {
+		id := string(mem[pos : pos+4])
+		size := int64(binary.LittleEndian.Uint32(mem[pos+4 : pos+8]))
+		pos += 8
+		if size > int64(len(mem)-pos) {
+			return fmt.Errorf("mixer: WAV %q chunk is truncated", id)
+		}
+		switch id {
+		case "fmt ":
+			fmtFound = true
+		case "data":
+			if !fmtFound {
+				return errors.New("mixer: WAV data chunk before fmt chunk")
+			}
+			return nil
+		}
+		pos += int(size)
+	}
+	return errors.New("mixer: WAV file has no data chunk")
+}
+
+func quickLoad(mem []byte, wav bool) (Chunk, error) {
+	if len(mem) == 0 {
+		return Chunk{}, errors.New("mixer: no audio data")
+	}
+	if wav {
+		if err := checkQuickWAV(mem); err != nil {
+			return Chunk{}, err
+		}
+	}
+	cmem, err := cCopy(mem)
+	if err != nil {
+		return Chunk{}, err
+	}
+
+	var c *C.Mix_Chunk
+	if wav {
+		c = C.Mix_QuickLoad_WAV((*C.Uint8)(cmem))
+	} else {
+		c = C.Mix_QuickLoad_RAW((*C.Uint8)(cmem), C.Uint32(len(mem)))
+	}
+	if c == nil {
+		C.SDL_free(cmem)
+		return Chunk{}, sdlError(0)
+	}
+
+	chunkMem.Lock()
+	chunkMem.m[c] = cmem
+	chunkMem.Unlock()
+	return Chunk{c}, nil
+}
+
+// QuickLoad_WAV creates a chunk from a WAV file in memory.  The WAV must
+// already be in the format the audio device was opened with, it is not
+// converted.  Only the layout of the file is checked, so that a truncated
+// file is rejected, not its format.  mem is copied.
+func QuickLoad_WAV(mem []byte) (Chunk, error) {
+	return quickLoad(mem, true)
+}
+
+// QuickLoad_RAW creates a chunk from samples in the format the audio device
+// was opened with.  mem is copied.
+func QuickLoad_RAW(mem []byte) (Chunk, error) {
+	return quickLoad(mem, false)
+}
+
+// NewChunkFromSamples creates a chunk from samples, an []int16 or a
+// []float32 of interleaved sample frames with channels channels played at
+// frequency frames per second.  The samples are converted to the format of
+// the audio device, so OpenAudio must be called first.  samples is copied,
+// it can be reused as soon as NewChunkFromSamples returns.
+func NewChunkFromSamples(samples interface{}, channels, frequency int) (Chunk, error) {
+	var format sdl.AudioFormat
+	var data []byte
+	switch s := samples.(type) {
+	case []int16:
+		format = sdl.AUDIO_S16SYS
+		data = sampleBytes(unsafe.Pointer(&s), len(s)*2)
+	case []float32:
+		format = sdl.AUDIO_F32SYS
+		data = sampleBytes(unsafe.Pointer(&s), len(s)*4)
+	default:
+		return Chunk{}, fmt.Errorf("mixer: samples must be []int16 or []float32, not %T", samples)
+	}
+
+	devFormat, devFrequency, devChannels, opened := QuerySpec()
+	if opened == 0 {
+		return Chunk{}, errors.New("mixer: audio is not open")
+	}
+	cvt, err := sdl.BuildAudioCVT(format, uint8(channels), frequency,
+		sdl.AudioFormat(devFormat), uint8(devChannels), devFrequency)
+	if err != nil {
+		return Chunk{}, err
+	}
+	data, err = cvt.Convert(data)
+	if err != nil {
+		return Chunk{}, err
+	}
+	return QuickLoad_RAW(data)
+}
+
+// sampleBytes returns the n bytes of the slice that header points to.
+func sampleBytes(header unsafe.Pointer, n int) []byte {
+	sh := (*reflect.SliceHeader)(header)
+	if n == 0 {
+		return nil
+	}
+	return byteSlice(unsafe.Pointer(sh.Data), n)
+}
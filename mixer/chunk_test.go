@@ -0,0 +1,59 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// wavChunk returns a RIFF chunk with the given id, size field, and data.
+func wavChunk(id string, size uint32, data []byte) []byte {
+	b := make([]byte, 8, 8+len(data))
+	copy(b, id)
+	binary.LittleEndian.PutUint32(b[4:], size)
+	return append(b, data...)
+}
+
+// wavFile returns a WAV file made of chunks.
+func wavFile(chunks ...[]byte) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WAVE")
+	for _, c := range chunks {
+		b = append(b, c...)
+	}
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(b)-8))
+	return b
+}
+
+func TestCheckQuickWAV(t *testing.T) {
+	format := wavChunk("fmt ", 16, make([]byte, 16))
+	samples := make([]byte, 64)
+
+	tests := []struct {
+		name string
+		mem  []byte
+		ok   bool
+	}{
+		{"valid", wavFile(format, wavChunk("data", 64, samples)), true},
+		{"extra chunk", wavFile(format, wavChunk("LIST", 4, make([]byte, 4)), wavChunk("data", 64, samples)), true},
+		{"header only", wavFile(format, wavChunk("data", 0, nil)), true},
+		{"short", wavFile(format)[:20], false},
+		{"not RIFF", append([]byte("RIFX"), wavFile(format, wavChunk("data", 64, samples))[4:]...), false},
+		{"truncated data", wavFile(format, wavChunk("data", 64, samples[:32])), false},
+		{"huge data", wavFile(format, wavChunk("data", 0xffffffff, samples)), false},
+		{"truncated chunk", wavFile(format, wavChunk("LIST", 1000, samples), wavChunk("data", 0, nil)), false},
+		{"no fmt", wavFile(wavChunk("LIST", 16, make([]byte, 16)), wavChunk("data", 64, samples)), false},
+		{"no data", wavFile(format, wavChunk("LIST", 64, samples)), false},
+	}
+	for _, test := range tests {
+		err := checkQuickWAV(test.mem)
+		if test.ok && err != nil {
+			t.Errorf("%s: got %v", test.name, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%s: got no error", test.name)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+import (
+	"grate/backend/sdl2"
+	"math"
+)
+
+// process calls f for every sample in b, with the sample scaled to the range
+// -1 to 1 and the index of its audio channel, and stores what f returns.
+// Only AUDIO_S16SYS and sdl.AUDIO_F32SYS are processed, other formats are
+// left as they are.
+func process(b SampleBuffer, f func(ch int, x float32) float32) {
+	if b.Channels <= 0 {
+		return
+	}
+
+	switch sdl.AudioFormat(b.Format) {
+	case sdl.AUDIO_S16SYS:
+		s := b.Int16()
+		for i, v := range s {
+			y := f(i%b.Channels, float32(v)/32768)
+			s[i] = int16(clamp(y) * 32767)
+		}
+	case sdl.AUDIO_F32SYS:
+		s := b.Float32()
+		for i, v := range s {
+			s[i] = f(i%b.Channels, v)
+		}
+	}
+}
+
+func clamp(x float32) float32 {
+	if x > 1 {
+		return 1
+	}
+	if x < -1 {
+		return -1
+	}
+	return x
+}
+
+// LowPass is a one pole low-pass filter that softens sounds above Cutoff
+// hertz.  Use one LowPass per channel, it keeps the state of the channel
+// between calls.
+type LowPass struct {
+	Cutoff float64
+
+	y []float32
+}
+
+// Process implements Effect.
+func (lp *LowPass) Process(channel int, samples SampleBuffer) {
+	if len(lp.y) != samples.Channels {
+		lp.y = make([]float32, samples.Channels)
+	}
+	a := float32(1 - math.Exp(-2*math.Pi*lp.Cutoff/float64(samples.Frequency)))
+	process(samples, func(ch int, x float32) float32 {
+		lp.y[ch] += a * (x - lp.y[ch])
+		return lp.y[ch]
+	})
+}
+
+// Bitcrush reduces the resolution of a sound to Bits bits and holds every
+// sample for Downsample frames, for a lo-fi sound.  Use one Bitcrush per
+// channel.
+type Bitcrush struct {
+	Bits       int
+	Downsample int
+
+	hold  []float32
+	count int
+}
+
+// Process implements Effect.
+func (bc *Bitcrush) Process(channel int, samples SampleBuffer) {
+	if len(bc.hold) != samples.Channels {
+		bc.hold = make([]float32, samples.Channels)
+	}
+	levels := float32(math.Exp2(float64(bc.Bits - 1)))
+	if bc.Bits <= 0 {
+		levels = 0
+	}
+	down := bc.Downsample
+	if down < 1 {
+		down = 1
+	}
+
+	process(samples, func(ch int, x float32) float32 {
+		if ch == 0 {
+			bc.count++
+		}
+		if bc.count >= down {
+			if ch == samples.Channels-1 {
+				bc.count = 0
+			}
+			if levels > 0 {
+				x = float32(math.Floor(float64(x*levels)+0.5)) / levels
+			}
+			bc.hold[ch] = x
+		}
+		return bc.hold[ch]
+	})
+}
+
+// Reverb adds a room sound to a channel, using the comb and all-pass filters
+// of a Schroeder reverberator.  Room is the size of the room from 0 to 1,
+// Damping how much the walls absorb high sounds from 0 to 1, and Wet how loud
+// the reverb is compared to the original sound.  Use one Reverb per channel.
+type Reverb struct {
+	Room    float32
+	Damping float32
+	Wet     float32
+
+	freq  int
+	state []reverbState
+}
+
+// Delays of the filters in seconds, from the Freeverb tuning at 44.1 kHz.
+var (
+	combDelays    = []float64{0.0253, 0.0269, 0.0290, 0.0307}
+	allpassDelays = []float64{0.0051, 0.0126}
+)
+
+type delayLine struct {
+	buf    []float32
+	pos    int
+	filter float32 // Low-pass state of comb filters
+}
+
+type reverbState struct {
+	combs     []delayLine
+	allpasses []delayLine
+}
+
+func newDelays(delays []float64, freq int) []delayLine {
+	d := make([]delayLine, len(delays))
+	for i, s := range delays {
+		d[i].buf = make([]float32, int(s*float64(freq))+1)
+	}
+	return d
+}
+
+// Process implements Effect.
+func (r *Reverb) Process(channel int, samples SampleBuffer) {
+	if r.freq != samples.Frequency || len(r.state) != samples.Channels {
+		r.freq = samples.Frequency
+		r.state = make([]reverbState, samples.Channels)
+		for i := range r.state {
+			r.state[i].combs = newDelays(combDelays, r.freq)
+			r.state[i].allpasses = newDelays(allpassDelays, r.freq)
+		}
+	}
+
+	feedback := 0.7 + 0.28*r.Room
+	damp := r.Damping
+	process(samples, func(ch int, x float32) float32 {
+		st := &r.state[ch]
+
+		var out float32
+		for i := range st.combs {
+			c := &st.combs[i]
+			y := c.buf[c.pos]
+			c.filter = y*(1-damp) + c.filter*damp
+			c.buf[c.pos] = x + c.filter*feedback
+			c.pos = (c.pos + 1) % len(c.buf)
+			out += y
+		}
+		out /= float32(len(st.combs))
+
+		for i := range st.allpasses {
+			a := &st.allpasses[i]
+			y := a.buf[a.pos]
+			a.buf[a.pos] = out + y*0.5
+			a.pos = (a.pos + 1) % len(a.buf)
+			out = y - out
+		}
+		return x + out*r.Wet
+	})
+}
+
+// PitchPoint is a point of a PitchEnvelope: Time seconds after the effect
+// starts the pitch is shifted by Semitones.
+type PitchPoint struct {
+	Time      float64
+	Semitones float64
+}
+
+// PitchEnvelope shifts the pitch of a sound over time, for example to make a
+// motor slow down or a sound rise as it plays.  The shift moves in straight
+// lines between Points, which must be sorted by Time, and holds the first
+// and last values before and after them.  Reset starts the envelope over.
+//
+// The shift uses a delay line of Window seconds, 0.05 if Window is 0, so the
+// sound is delayed by up to Window seconds and slightly coloured.  Use one
+// PitchEnvelope per channel.
+type PitchEnvelope struct {
+	Points []PitchPoint
+	Window float64
+
+	freq   int
+	frames int // Frames processed since the start
+	phase  float64
+	bufs   [][]float32
+	pos    int
+}
+
+// Reset starts the envelope over from its first point.
+func (pe *PitchEnvelope) Reset() {
+	pe.frames = 0
+	pe.phase = 0
+}
+
+// Semitones returns the pitch shift at t seconds.
+func (pe *PitchEnvelope) Semitones(t float64) float64 {
+	pts := pe.Points
+	if len(pts) == 0 {
+		return 0
+	}
+	if t <= pts[0].Time {
+		return pts[0].Semitones
+	}
+	for i := 1; i < len(pts); i++ {
+		if t < pts[i].Time {
+			a, b := pts[i-1], pts[i]
+			return a.Semitones + (b.Semitones-a.Semitones)*(t-a.Time)/(b.Time-a.Time)
+		}
+	}
+	return pts[len(pts)-1].Semitones
+}
+
+// Process implements Effect.
+func (pe *PitchEnvelope) Process(channel int, samples SampleBuffer) {
+	window := pe.Window
+	if window <= 0 {
+		window = 0.05
+	}
+	size := int(window*float64(samples.Frequency)) + 2
+	if pe.freq != samples.Frequency || len(pe.bufs) != samples.Channels ||
+		len(pe.bufs) > 0 && len(pe.bufs[0]) != size {
+		pe.freq = samples.Frequency
+		pe.bufs = make([][]float32, samples.Channels)
+		for i := range pe.bufs {
+			pe.bufs[i] = make([]float32, size)
+		}
+		pe.pos = 0
+	}
+	if samples.Frequency <= 0 {
+		return
+	}
+
+	// Two taps read the delay line half a window apart while their delays
+	// change at the rate that shifts the pitch.  Each fades out when its
+	// delay wraps around, and the fades add up to 1.
+	delay := float64(size - 2)
+	var step float64
+	process(samples, func(ch int, x float32) float32 {
+		if ch == 0 {
+			t := float64(pe.frames) / float64(pe.freq)
+			ratio := math.Exp2(pe.Semitones(t) / 12)
+			step = (ratio - 1) / delay
+		}
+
+		buf := pe.bufs[ch]
+		buf[pe.pos] = x
+		var y float64
+		for _, offset := range [2]float64{0, 0.5} {
+			p := pe.phase + offset
+			p -= math.Floor(p)
+			w := math.Sin(math.Pi * p)
+			y += w * w * float64(tap(buf, pe.pos, p*delay))
+		}
+
+		if ch == samples.Channels-1 {
+			pe.frames++
+			pe.pos = (pe.pos + 1) % len(buf)
+			pe.phase -= step
+			pe.phase -= math.Floor(pe.phase)
+		}
+		return float32(y)
+	})
+}
+
+// tap reads buf, a delay line last written at pos, d samples back.
+func tap(buf []float32, pos int, d float64) float32 {
+	i := int(d)
+	frac := float32(d - float64(i))
+	n := len(buf)
+	a := buf[(pos-i+n)%n]
+	b := buf[(pos-i-1+n)%n]
+	return a + (b-a)*frac
+}
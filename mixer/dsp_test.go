@@ -0,0 +1,199 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+import (
+	"grate/backend/sdl2"
+	"math"
+	"testing"
+)
+
+const testFreq = 44100
+
+// floatBuffer returns a SampleBuffer in sdl.AUDIO_F32SYS holding samples.
+func floatBuffer(channels int, samples []float32) SampleBuffer {
+	b := SampleBuffer{
+		Format:    AudioFormat(sdl.AUDIO_F32SYS),
+		Channels:  channels,
+		Frequency: testFreq,
+		Bytes:     make([]byte, 4*len(samples)),
+	}
+	copy(b.Float32(), samples)
+	return b
+}
+
+// sine returns n samples of a sine wave of freq hertz.
+func sine(freq float64, n int) []float32 {
+	s := make([]float32, n)
+	for i := range s {
+		s[i] = float32(math.Sin(2 * math.Pi * freq * float64(i) / testFreq))
+	}
+	return s
+}
+
+func rms(s []float32) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(s)))
+}
+
+func crossings(s []float32) int {
+	n := 0
+	for i := 1; i < len(s); i++ {
+		if s[i-1] < 0 && s[i] >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestProcess(t *testing.T) {
+	b := SampleBuffer{Format: AUDIO_S16SYS, Channels: 2, Frequency: testFreq,
+		Bytes: make([]byte, 8)}
+	copy(b.Int16(), []int16{0, 16384, -16384, 100})
+
+	var chans []int
+	process(b, func(ch int, x float32) float32 {
+		chans = append(chans, ch)
+		return x * 4
+	})
+	if want := []int{0, 1, 0, 1}; len(chans) != 4 || chans[0] != want[0] ||
+		chans[1] != want[1] || chans[2] != want[2] || chans[3] != want[3] {
+		t.Errorf("got channels %v, want %v", chans, want)
+	}
+	got := b.Int16()
+	if got[0] != 0 || got[1] != 32767 || got[2] != -32767 || got[3] < 398 || got[3] > 400 {
+		t.Errorf("got %v, want [0 32767 -32767 399]", got)
+	}
+
+	u8 := SampleBuffer{Format: AUDIO_U8, Channels: 1, Bytes: []byte{1, 2}}
+	process(u8, func(ch int, x float32) float32 { return 0 })
+	if u8.Bytes[0] != 1 || u8.Bytes[1] != 2 {
+		t.Errorf("AUDIO_U8 was processed: %v", u8.Bytes)
+	}
+}
+
+func TestLowPass(t *testing.T) {
+	dc := make([]float32, testFreq/10)
+	for i := range dc {
+		dc[i] = 0.5
+	}
+	b := floatBuffer(1, dc)
+	(&LowPass{Cutoff: 1000}).Process(0, b)
+	if got := b.Float32()[len(dc)-1]; math.Abs(float64(got)-0.5) > 0.001 {
+		t.Errorf("DC: got %v, want 0.5", got)
+	}
+
+	for _, test := range []struct {
+		freq     float64
+		min, max float64
+	}{
+		{100, 0.65, 0.72},
+		{10000, 0, 0.1},
+	} {
+		b := floatBuffer(1, sine(test.freq, testFreq/10))
+		(&LowPass{Cutoff: 1000}).Process(0, b)
+		if got := rms(b.Float32()[testFreq/20:]); got < test.min || got > test.max {
+			t.Errorf("%v Hz: got RMS %v, want %v to %v", test.freq, got, test.min, test.max)
+		}
+	}
+}
+
+func TestBitcrush(t *testing.T) {
+	b := floatBuffer(1, []float32{0.3, 0.9, -0.3, 0.1, -0.8, 0.3})
+	(&Bitcrush{Bits: 2, Downsample: 2}).Process(0, b)
+	want := []float32{0, 1, 1, 0, 0, 0.5}
+	for i, v := range b.Float32() {
+		if v != want[i] {
+			t.Fatalf("got %v, want %v", b.Float32(), want)
+		}
+	}
+
+	b = floatBuffer(2, []float32{0.3, -0.3, 0.6, -0.6})
+	(&Bitcrush{Bits: 3}).Process(0, b)
+	want = []float32{0.25, -0.25, 0.5, -0.5}
+	for i, v := range b.Float32() {
+		if v != want[i] {
+			t.Fatalf("stereo: got %v, want %v", b.Float32(), want)
+		}
+	}
+}
+
+func TestReverb(t *testing.T) {
+	in := sine(440, testFreq/10)
+	b := floatBuffer(1, in)
+	(&Reverb{Room: 0.5, Damping: 0.5}).Process(0, b)
+	for i, v := range b.Float32() {
+		if v != in[i] {
+			t.Fatalf("Wet 0 changed sample %d from %v to %v", i, in[i], v)
+		}
+	}
+
+	impulse := make([]float32, testFreq/2)
+	impulse[0] = 1
+	b = floatBuffer(1, impulse)
+	(&Reverb{Room: 0.5, Damping: 0.5, Wet: 1}).Process(0, b)
+	if tail := rms(b.Float32()[testFreq/10:]); tail == 0 {
+		t.Error("no reverb tail after an impulse")
+	}
+	if tail := rms(b.Float32()[testFreq/4:]); tail > rms(b.Float32()[testFreq/10:testFreq/4]) {
+		t.Error("reverb tail does not decay")
+	}
+}
+
+func TestPitchEnvelopeSemitones(t *testing.T) {
+	pe := PitchEnvelope{Points: []PitchPoint{{0.5, 0}, {1.5, 12}, {2, -12}}}
+	for _, test := range []struct {
+		t, want float64
+	}{
+		{0, 0},
+		{0.5, 0},
+		{1, 6},
+		{1.5, 12},
+		{1.75, 0},
+		{3, -12},
+	} {
+		if got := pe.Semitones(test.t); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("Semitones(%v) = %v, want %v", test.t, got, test.want)
+		}
+	}
+	if got := (&PitchEnvelope{}).Semitones(1); got != 0 {
+		t.Errorf("no points: got %v, want 0", got)
+	}
+}
+
+func TestPitchEnvelope(t *testing.T) {
+	in := sine(440, testFreq)
+	for _, test := range []struct {
+		semitones float64
+		ratio     float64
+	}{
+		{0, 1},
+		{12, 2},
+		{-12, 0.5},
+	} {
+		b := floatBuffer(1, in)
+		pe := &PitchEnvelope{Points: []PitchPoint{{0, test.semitones}}}
+		// Process in small buffers, as the mixer does.
+		for i := 0; i < len(b.Bytes); i += 4096 {
+			part := b
+			part.Bytes = b.Bytes[i:]
+			if len(part.Bytes) > 4096 {
+				part.Bytes = part.Bytes[:4096]
+			}
+			pe.Process(0, part)
+		}
+		out := b.Float32()[testFreq/10:]
+		got := float64(crossings(out)) / float64(crossings(in[testFreq/10:]))
+		if math.Abs(got-test.ratio) > 0.05*test.ratio {
+			t.Errorf("%v semitones: frequency changed by %v, want %v", test.semitones, got, test.ratio)
+		}
+		if r := rms(out); r < 0.5 || r > 0.9 {
+			t.Errorf("%v semitones: got RMS %v, want about 0.7", test.semitones, r)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "effect.h"
+#include "_cgo_export.h"
+
+static void effectCallback(int channel, void *stream, int len, void *udata) {
+	goEffect(channel, stream, len);
+}
+
+static void effectDone(int channel, void *udata) {
+	goEffectDone(channel, (uintptr_t)udata);
+}
+
+int registerEffect(int channel, uintptr_t gen) {
+	return Mix_RegisterEffect(channel, effectCallback, effectDone, (void *)gen);
+}
+
+int unregisterEffect(int channel) {
+	return Mix_UnregisterEffect(channel, effectCallback);
+}
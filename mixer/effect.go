@@ -0,0 +1,220 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "effect.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"errors"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// CHANNEL_POST is the channel of effects that process the final mix, after
+// all channels and the music have been mixed together.
+const CHANNEL_POST = C.MIX_CHANNEL_POST
+
+// SampleBuffer is the audio an Effect processes, in the format the audio
+// device was opened with.
+type SampleBuffer struct {
+	Format    AudioFormat
+	Channels  int // Number of interleaved audio channels
+	Frequency int // Sample frames per second
+	Bytes     []byte
+}
+
+// Frames returns the number of sample frames in b.
+func (b SampleBuffer) Frames() int {
+	size := b.Channels * sdl.AudioFormat(b.Format).BitSize() / 8
+	if size == 0 {
+		return 0
+	}
+	return len(b.Bytes) / size
+}
+
+// Int16 returns the samples of b as int16s, without copying.  Use it when
+// Format is AUDIO_S16SYS, the default format.
+func (b SampleBuffer) Int16() []int16 {
+	return sdl.AudioBuffer(b.Bytes).Int16()
+}
+
+// Float32 returns the samples of b as float32s, without copying.  Use it
+// when Format is sdl.AUDIO_F32SYS.
+func (b SampleBuffer) Float32() []float32 {
+	return sdl.AudioBuffer(b.Bytes).Float32()
+}
+
+// Effect processes the audio of a channel in place.
+//
+// Process is called on the audio thread with the audio device locked, so it
+// must return quickly and must not call mixer functions.  samples is only
+// valid until Process returns.
+type Effect interface {
+	Process(channel int, samples SampleBuffer)
+}
+
+// EffectFunc is a function that is an Effect.
+type EffectFunc func(channel int, samples SampleBuffer)
+
+// Process calls f(channel, samples).
+func (f EffectFunc) Process(channel int, samples SampleBuffer) {
+	f(channel, samples)
+}
+
+// EffectID identifies an effect registered with RegisterEffect.
+type EffectID uint64
+
+type registeredEffect struct {
+	id     EffectID
+	effect Effect
+}
+
+// channelEffects is the Go side of the effects on one channel.  One C
+// callback is registered with the mixer per channel, it runs the effects in
+// the order they were registered.
+type channelEffects struct {
+	effects []registeredEffect // Replaced, never modified, so the audio thread can keep a copy
+	gen     uintptr            // Identifies the C registration, 0 if none
+	spec    SampleBuffer       // Format of the device when the callback was registered
+}
+
+// effects holds the effects of every channel.  The mixer calls the effects
+// with the audio device locked, and locks the device itself in
+// Mix_RegisterEffect and Mix_UnregisterEffect, so mu must never be held
+// while calling the mixer.
+var effects = struct {
+	mu      sync.Mutex
+	m       map[int]*channelEffects
+	lastID  EffectID
+	lastGen uintptr
+}{m: make(map[int]*channelEffects)}
+
+// RegisterEffect adds e to the effects of channel, or to the final mix if
+// channel is CHANNEL_POST.  Effects run in the order they were added.  Like
+// the effects of SDL_mixer, the effects of a channel are removed when the
+// channel stops playing, so add them after starting a chunk.  The effects of
+// CHANNEL_POST are only removed by UnregisterEffect.
+//
+// OpenAudio must be called before RegisterEffect.
+func RegisterEffect(channel int, e Effect) (EffectID, error) {
+	format, frequency, channels, opened := QuerySpec()
+	if opened == 0 {
+		return 0, errors.New("mixer: audio is not open")
+	}
+
+	effects.mu.Lock()
+	effects.lastID++
+	id := effects.lastID
+	ce, ok := effects.m[channel]
+	if !ok {
+		ce = &channelEffects{}
+		effects.m[channel] = ce
+	}
+	ce.effects = append(ce.effects[:len(ce.effects):len(ce.effects)],
+		registeredEffect{id, e})
+	var gen uintptr
+	if ce.gen == 0 {
+		effects.lastGen++
+		gen = effects.lastGen
+		ce.gen = gen
+		ce.spec = SampleBuffer{Format: format, Channels: channels,
+			Frequency: frequency}
+	}
+	effects.mu.Unlock()
+
+	if gen != 0 && C.registerEffect(C.int(channel), C.uintptr_t(gen)) == 0 {
+		err := sdlError(0)
+		effects.mu.Lock()
+		if ce.gen == gen {
+			delete(effects.m, channel)
+		}
+		effects.mu.Unlock()
+		return 0, err
+	}
+	return id, nil
+}
+
+// UnregisterEffect removes the effect id from channel.  It does nothing if
+// the effect was already removed.
+func UnregisterEffect(channel int, id EffectID) error {
+	effects.mu.Lock()
+	ce, ok := effects.m[channel]
+	if !ok {
+		effects.mu.Unlock()
+		return nil
+	}
+	list := make([]registeredEffect, 0, len(ce.effects))
+	for _, r := range ce.effects {
+		if r.id != id {
+			list = append(list, r)
+		}
+	}
+	ce.effects = list
+	empty := len(list) == 0
+	if empty {
+		delete(effects.m, channel)
+	}
+	effects.mu.Unlock()
+
+	if empty && C.unregisterEffect(C.int(channel)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+// UnregisterAllEffects removes all the effects registered with
+// RegisterEffect from channel.
+func UnregisterAllEffects(channel int) error {
+	effects.mu.Lock()
+	_, ok := effects.m[channel]
+	delete(effects.m, channel)
+	effects.mu.Unlock()
+
+	if ok && C.unregisterEffect(C.int(channel)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+//export goEffect
+func goEffect(channel C.int, stream unsafe.Pointer, n C.int) {
+	effects.mu.Lock()
+	ce, ok := effects.m[int(channel)]
+	var list []registeredEffect
+	var buf SampleBuffer
+	if ok {
+		list, buf = ce.effects, ce.spec
+	}
+	effects.mu.Unlock()
+
+	if len(list) == 0 {
+		return
+	}
+	buf.Bytes = byteSlice(stream, int(n))
+	for _, r := range list {
+		r.effect.Process(int(channel), buf)
+	}
+}
+
+//export goEffectDone
+func goEffectDone(channel C.int, gen C.uintptr_t) {
+	effects.mu.Lock()
+	if ce, ok := effects.m[int(channel)]; ok && ce.gen == uintptr(gen) {
+		delete(effects.m, int(channel))
+	}
+	effects.mu.Unlock()
+}
+
+func byteSlice(ptr unsafe.Pointer, n int) []byte {
+	b := []byte{}
+	sh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	sh.Data = uintptr(ptr)
+	sh.Len = n
+	sh.Cap = n
+	return b
+}
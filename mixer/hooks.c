@@ -0,0 +1,40 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "hooks.h"
+
+// The hooks run on the audio thread while the mixer holds the audio lock, so
+// they only push an event and leave the work to Go.
+static Uint32 channelEventType;
+static Uint32 musicEventType;
+
+static void channelFinished(int channel) {
+	SDL_Event event;
+	SDL_zero(event);
+	event.type = channelEventType;
+	event.user.code = channel;
+	event.user.data1 = Mix_GetChunk(channel);
+	SDL_PushEvent(&event);
+}
+
+static void musicFinished(void) {
+	SDL_Event event;
+	SDL_zero(event);
+	event.type = musicEventType;
+	SDL_PushEvent(&event);
+}
+
+Mix_Chunk *eventChunk(uintptr_t data1) {
+	return (Mix_Chunk *)data1;
+}
+
+void hookChannelFinished(Uint32 type) {
+	channelEventType = type;
+	Mix_ChannelFinished(type != 0 ? channelFinished : NULL);
+}
+
+void hookMusicFinished(Uint32 type) {
+	musicEventType = type;
+	Mix_HookMusicFinished(type != 0 ? musicFinished : NULL);
+}
@@ -0,0 +1,105 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "hooks.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"sync"
+)
+
+// The event types pushed by the finished hooks, registered on first use.
+var (
+	hookEventsOnce      sync.Once
+	channelFinishedType sdl.EventType
+	musicFinishedType   sdl.EventType
+)
+
+func hookEvents() error {
+	hookEventsOnce.Do(func() {
+		t := sdl.RegisterEvents(2)
+		if t == 0xFFFFFFFF {
+			return
+		}
+		channelFinishedType = t
+		musicFinishedType = t + 1
+	})
+	if channelFinishedType == 0 {
+		return sdl.SDLError{Msg: "mixer: no user event types left"}
+	}
+	return nil
+}
+
+// HookChannelFinished makes the mixer push an event each time a channel
+// stops playing, because its chunk ended, it expired, or it was halted.  The
+// event is an sdl.UserEvent of the returned type, use ChannelFinished to read
+// it.  The event type is allocated with sdl.RegisterEvents the first time a
+// hook is installed and stays the same afterwards.
+//
+// The mixer calls its hook on the audio thread while it holds the audio
+// lock.  Go code run there could stall the audio, or deadlock with a
+// goroutine that is waiting for the lock, so the hook only pushes an event.
+// Events are delivered to the thread that reads the event queue.
+func HookChannelFinished() (sdl.EventType, error) {
+	if err := hookEvents(); err != nil {
+		return 0, err
+	}
+	C.hookChannelFinished(C.Uint32(channelFinishedType))
+	return channelFinishedType, nil
+}
+
+// UnhookChannelFinished stops the events installed by HookChannelFinished.
+// Events already in the queue are still delivered.
+func UnhookChannelFinished() {
+	C.hookChannelFinished(0)
+}
+
+// HookMusicFinished makes the mixer push an event when the music ends or is
+// halted.  The event is an sdl.UserEvent of the returned type, use
+// MusicFinished to test for it.  See HookChannelFinished.
+func HookMusicFinished() (sdl.EventType, error) {
+	if err := hookEvents(); err != nil {
+		return 0, err
+	}
+	C.hookMusicFinished(C.Uint32(musicFinishedType))
+	return musicFinishedType, nil
+}
+
+// UnhookMusicFinished stops the events installed by HookMusicFinished.
+func UnhookMusicFinished() {
+	C.hookMusicFinished(0)
+}
+
+// ChannelFinished returns the channel that stopped and the chunk it was
+// playing if event was pushed by the HookChannelFinished hook.  ok is false
+// for all other events.  The chunk may have been freed since the event was
+// pushed.
+func ChannelFinished(event sdl.Event) (channel int, chunk Chunk, ok bool) {
+	e, ok := userEvent(event)
+	if !ok || channelFinishedType == 0 || e.Type != channelFinishedType {
+		return 0, Chunk{}, false
+	}
+	return int(e.Code), Chunk{C.eventChunk(C.uintptr_t(e.Data1))}, true
+}
+
+// MusicFinished returns true if event was pushed by the HookMusicFinished
+// hook.
+func MusicFinished(event sdl.Event) bool {
+	e, ok := userEvent(event)
+	return ok && musicFinishedType != 0 && e.Type == musicFinishedType
+}
+
+func userEvent(event sdl.Event) (*sdl.UserEvent, bool) {
+	switch e := event.(type) {
+	case *sdl.UserEvent:
+		return e, true
+	case *sdl.EventUnion:
+		e2, ok := e.Convert().(*sdl.UserEvent)
+		return e2, ok
+	}
+	return nil, false
+}
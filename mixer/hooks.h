@@ -0,0 +1,9 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL2/SDL_mixer.h"
+
+extern void hookChannelFinished(Uint32 type);
+extern void hookMusicFinished(Uint32 type);
+extern Mix_Chunk *eventChunk(uintptr_t data1);
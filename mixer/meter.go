@@ -0,0 +1,118 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+import (
+	"grate/backend/sdl2"
+	"math"
+	"math/cmplx"
+)
+
+// sampleReader returns the number of samples in b and a function that
+// returns sample i scaled to the range -1 to 1.  Only AUDIO_S16SYS and
+// sdl.AUDIO_F32SYS can be read, other formats have no samples.
+func sampleReader(b SampleBuffer) (n int, at func(i int) float64) {
+	switch sdl.AudioFormat(b.Format) {
+	case sdl.AUDIO_S16SYS:
+		s := b.Int16()
+		return len(s), func(i int) float64 { return float64(s[i]) / 32768 }
+	case sdl.AUDIO_F32SYS:
+		s := b.Float32()
+		return len(s), func(i int) float64 { return float64(s[i]) }
+	}
+	return 0, nil
+}
+
+// Levels returns the RMS and peak level of every audio channel of b, from 0
+// to 1.  Only AUDIO_S16SYS and sdl.AUDIO_F32SYS audio can be measured, other
+// formats measure as silence.
+func Levels(b SampleBuffer) (rms, peak []float64) {
+	if b.Channels <= 0 {
+		return nil, nil
+	}
+	rms = make([]float64, b.Channels)
+	peak = make([]float64, b.Channels)
+
+	n, at := sampleReader(b)
+	for i := 0; i < n; i++ {
+		v := at(i)
+		ch := i % b.Channels
+		rms[ch] += v * v
+		if a := math.Abs(v); a > peak[ch] {
+			peak[ch] = a
+		}
+	}
+
+	frames := n / b.Channels
+	for ch := range rms {
+		if frames > 0 {
+			rms[ch] = math.Sqrt(rms[ch] / float64(frames))
+		}
+	}
+	return rms, peak
+}
+
+// Spectrum returns the magnitude spectrum of the first size frames of b, with
+// the audio channels mixed together.  size is rounded up to a power of two,
+// at least 2, and Spectrum returns size/2 bins: bin i is the frequency
+// i*b.Frequency/size.  A Hann window is applied first.  Missing frames are
+// silence.
+func Spectrum(b SampleBuffer, size int) []float64 {
+	pow := 2
+	for pow < size {
+		pow <<= 1
+	}
+	size = pow
+
+	x := make([]complex128, size)
+	n, at := sampleReader(b)
+	if b.Channels > 0 {
+		for f := 0; f < size && (f+1)*b.Channels <= n; f++ {
+			var v float64
+			for ch := 0; ch < b.Channels; ch++ {
+				v += at(f*b.Channels + ch)
+			}
+			w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(f)/float64(size-1))
+			x[f] = complex(v/float64(b.Channels)*w, 0)
+		}
+	}
+
+	fft(x)
+	bins := make([]float64, size/2)
+	for i := range bins {
+		bins[i] = cmplx.Abs(x[i]) * 2 / float64(size)
+	}
+	return bins
+}
+
+// fft transforms x in place with the radix-2 Cooley-Tukey algorithm.
+// len(x) must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(length)))
+		for i := 0; i < n; i += length {
+			wk := complex(1, 0)
+			for k := 0; k < length/2; k++ {
+				u := x[i+k]
+				v := x[i+k+length/2] * wk
+				x[i+k] = u + v
+				x[i+k+length/2] = u - v
+				wk *= w
+			}
+		}
+	}
+}
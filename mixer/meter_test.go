@@ -0,0 +1,146 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	s16 := SampleBuffer{Format: AUDIO_S16SYS, Channels: 2, Bytes: make([]byte, 8)}
+	copy(s16.Int16(), []int16{16384, 0, -16384, -32768})
+
+	tests := []struct {
+		name      string
+		b         SampleBuffer
+		rms, peak []float64
+	}{
+		{"silence", floatBuffer(1, make([]float32, 16)), []float64{0}, []float64{0}},
+		{"square", floatBuffer(1, []float32{0.5, -0.5, 0.5, -0.5}), []float64{0.5}, []float64{0.5}},
+		{"stereo", floatBuffer(2, []float32{1, 0.25, -1, 0, 1, 0, -1, 0}), []float64{1, 0.125}, []float64{1, 0.25}},
+		{"s16", s16, []float64{0.5, math.Sqrt(0.5)}, []float64{0.5, 1}},
+		{"empty", floatBuffer(2, nil), []float64{0, 0}, []float64{0, 0}},
+		{"no channels", SampleBuffer{Format: AUDIO_S16SYS}, nil, nil},
+		{"u8", SampleBuffer{Format: AUDIO_U8, Channels: 1, Bytes: []byte{255}}, []float64{0}, []float64{0}},
+	}
+	for _, test := range tests {
+		rms, peak := Levels(test.b)
+		if !near(rms, test.rms, 1e-6) || !near(peak, test.peak, 1e-6) {
+			t.Errorf("%s: got %v %v, want %v %v", test.name, rms, peak, test.rms, test.peak)
+		}
+	}
+}
+
+func TestSpectrum(t *testing.T) {
+	// 1378.125 Hz is bin 32 of a 1024 point spectrum at 44.1 kHz.
+	in := sine(1378.125, 1024)
+	stereo := make([]float32, 2*len(in))
+	for i, v := range in {
+		stereo[2*i] = v
+		stereo[2*i+1] = v
+	}
+
+	for _, test := range []struct {
+		name string
+		b    SampleBuffer
+		size int
+	}{
+		{"mono", floatBuffer(1, in), 1024},
+		{"stereo", floatBuffer(2, stereo), 1024},
+		{"rounded size", floatBuffer(1, in), 1000},
+	} {
+		bins := Spectrum(test.b, test.size)
+		if len(bins) != 512 {
+			t.Errorf("%s: got %d bins, want 512", test.name, len(bins))
+			continue
+		}
+		peak := 0
+		for i, v := range bins {
+			if v > bins[peak] {
+				peak = i
+			}
+		}
+		// The Hann window halves the amplitude of the peak.
+		if peak != 32 || math.Abs(bins[peak]-0.5) > 0.01 {
+			t.Errorf("%s: got peak %v in bin %d, want 0.5 in bin 32", test.name, bins[peak], peak)
+		}
+		if bins[100] > 0.001 {
+			t.Errorf("%s: got %v in bin 100, want about 0", test.name, bins[100])
+		}
+	}
+
+	for _, test := range []struct {
+		size, bins int
+	}{
+		{-1, 1}, {0, 1}, {2, 1}, {3, 2}, {1025, 1024},
+	} {
+		if bins := Spectrum(floatBuffer(1, in), test.size); len(bins) != test.bins {
+			t.Errorf("size %d: got %d bins, want %d", test.size, len(bins), test.bins)
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []complex128
+		want []complex128
+	}{
+		{"one", []complex128{3}, []complex128{3}},
+		{"impulse", []complex128{1, 0, 0, 0}, []complex128{1, 1, 1, 1}},
+		{"dc", []complex128{1, 1, 1, 1}, []complex128{4, 0, 0, 0}},
+		{"nyquist", []complex128{1, -1, 1, -1}, []complex128{0, 0, 4, 0}},
+		{"quarter", []complex128{1, 1i, -1, -1i}, []complex128{0, 4, 0, 0}},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := make([]complex128, 64)
+	for i := range random {
+		random[i] = complex(r.Float64()*2-1, r.Float64()*2-1)
+	}
+	tests = append(tests, struct {
+		name string
+		in   []complex128
+		want []complex128
+	}{"random", random, dft(random)})
+
+	for _, test := range tests {
+		x := append([]complex128(nil), test.in...)
+		fft(x)
+		for i := range x {
+			if cmplx.Abs(x[i]-test.want[i]) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", test.name, x, test.want)
+				break
+			}
+		}
+	}
+}
+
+// dft is the slow discrete Fourier transform that fft must match.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		for j, v := range x {
+			out[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+		}
+	}
+	return out
+}
+
+func near(got, want []float64, tolerance float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
@@ -140,6 +140,7 @@ func OpenAudio(frequency int, format AudioFormat, channels, chunksize int) error
 	if r != 0 {
 		return sdlError(int(r))
 	}
+	openChunkSize = chunksize
 	return nil
 }
 
@@ -178,8 +179,36 @@ func LoadWAV(file string) (Chunk, error) {
 	return Chunk{r}, nil
 }
 
-// LoadWAVFromReader loads an io.Reader into a Chunk.
+// LoadWAV_RW loads src into a Chunk.  src can be a WAVE, AIFF, RIFF, OGG,
+// or VOC file.  If freesrc is true src is closed after it has been read,
+// even if there is an error.
+func LoadWAV_RW(src *sdl.RWops, freesrc bool) (Chunk, error) {
+	var fs C.int
+	if freesrc {
+		fs = 1
+	}
+
+	r := C.Mix_LoadWAV_RW((*C.SDL_RWops)(unsafe.Pointer(src)), fs)
+	if r == nil {
+		return Chunk{}, sdlError(0)
+	}
+	return Chunk{r}, nil
+}
+
+// LoadWAVFromReader loads an io.Reader into a Chunk.  If reader is also an
+// io.Seeker the data is streamed from it, otherwise reader is read into
+// memory first.  reader is not closed.
 func LoadWAVFromReader(reader io.Reader) (Chunk, error) {
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		// Hide Close, and Write, from the RWops so freeing it leaves
+		// reader open.
+		src, err := sdl.RWFromReader(struct{ io.ReadSeeker }{rs})
+		if err != nil {
+			return Chunk{}, err
+		}
+		return LoadWAV_RW(src, true)
+	}
+
 	buff, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return Chunk{}, err
@@ -187,11 +216,11 @@ func LoadWAVFromReader(reader io.Reader) (Chunk, error) {
 	if len(buff) == 0 {
 		return Chunk{}, errors.New("io.Reader is empty, no chunk created.")
 	}
-	r := C.Mix_LoadWAV_RW(C.SDL_RWFromMem(unsafe.Pointer(&buff[0]), C.int(len(buff))), 1)
-	if r == nil {
-		return Chunk{}, sdlError(0)
+	src, err := sdl.RWFromMem(buff)
+	if err != nil {
+		return Chunk{}, err
 	}
-	return Chunk{r}, nil
+	return LoadWAV_RW(src, true)
 }
 
 // LoadMUS loads a file into a Music.
@@ -206,7 +235,26 @@ func LoadMUS(file string) (Music, error) {
 	return Music{r}, nil
 }
 
-// LoadMUSFromReader loads an io.Reader into a Music.
+// LoadMUS_RW loads src into a Music.  The music keeps reading from src while
+// it plays, so src must stay open until the music is freed.  If freesrc is
+// true src is closed when the music is freed, or right away if there is an
+// error.
+func LoadMUS_RW(src *sdl.RWops, freesrc bool) (Music, error) {
+	var fs C.int
+	if freesrc {
+		fs = 1
+	}
+
+	r := C.Mix_LoadMUS_RW((*C.SDL_RWops)(unsafe.Pointer(src)), fs)
+	if r == nil {
+		return Music{}, sdlError(0)
+	}
+	return Music{r}, nil
+}
+
+// LoadMUSFromReader loads an io.Reader into a Music.  reader is read to the
+// end and the music plays from a copy in memory, so reader can be closed
+// right away.  Use LoadMUSFromReadSeeker to stream large files instead.
 func LoadMUSFromReader(reader io.Reader) (Music, error) {
 	buff, err := ioutil.ReadAll(reader)
 	if err != nil {
@@ -215,16 +263,33 @@ func LoadMUSFromReader(reader io.Reader) (Music, error) {
 	if len(buff) == 0 {
 		return Music{}, errors.New("io.Reader is empty, no music created.")
 	}
-	r := C.Mix_LoadMUS_RW(C.SDL_RWFromMem(unsafe.Pointer(&buff[0]), C.int(len(buff))), C.int(0))
-	if r == nil {
-		return Music{}, sdlError(0)
+	src, err := sdl.RWFromMem(buff)
+	if err != nil {
+		return Music{}, err
 	}
-	return Music{r}, nil
+	return LoadMUS_RW(src, true)
+}
+
+// LoadMUSFromReadSeeker loads a Music that streams from reader while it
+// plays, so large files are not copied into memory.  reader is kept open for
+// the life of the Music: it is read from the audio thread, and closed when
+// the Music is freed if it is an io.Closer.  reader must not be used by
+// anything else until then.
+func LoadMUSFromReadSeeker(reader io.ReadSeeker) (Music, error) {
+	src, err := sdl.RWFromReader(reader)
+	if err != nil {
+		return Music{}, err
+	}
+	return LoadMUS_RW(src, true)
 }
 
 // Free frees c.
 func (c Chunk) Free() {
+	mem := takeChunkMem(c.ptr)
 	C.Mix_FreeChunk(c.ptr)
+	if mem != nil {
+		C.SDL_free(mem)
+	}
 	c.ptr = nil
 }
 
@@ -271,28 +336,73 @@ func ReserveChannels(num int) int {
 	return int(C.Mix_ReserveChannels(C.int(num)))
 }
 
+// GroupChannel adds which channel to group tag, or removes it from its group
+// if tag is -1.
+func GroupChannel(which, tag int) error {
+	if C.Mix_GroupChannel(C.int(which), C.int(tag)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+// GroupChannels adds the channels from through to to group tag, or removes
+// them from their group if tag is -1.  It returns the number of channels
+// that were changed.
+func GroupChannels(from, to, tag int) int {
+	return int(C.Mix_GroupChannels(C.int(from), C.int(to), C.int(tag)))
+}
+
+// GroupAvailable returns the first channel in group tag that is not playing,
+// or -1 if all of them are playing.
+func GroupAvailable(tag int) int {
+	return int(C.Mix_GroupAvailable(C.int(tag)))
+}
+
+// GroupCount returns the number of channels in group tag, or the number of
+// channels if tag is -1.
+func GroupCount(tag int) int {
+	return int(C.Mix_GroupCount(C.int(tag)))
+}
+
+// GroupOldest returns the channel in group tag that has been playing the
+// longest, or -1 if none are playing.
+func GroupOldest(tag int) int {
+	return int(C.Mix_GroupOldest(C.int(tag)))
+}
+
+// GroupNewer returns the channel in group tag that started playing last, or
+// -1 if none are playing.
+func GroupNewer(tag int) int {
+	return int(C.Mix_GroupNewer(C.int(tag)))
+}
+
+// FadeOutGroup fades out the channels in group tag over ms milliseconds.  It
+// returns the number of channels set to fade out.
+func FadeOutGroup(tag, ms int) int {
+	return int(C.Mix_FadeOutGroup(C.int(tag), C.int(ms)))
+}
+
+// HaltGroup halts the channels in group tag.
+func HaltGroup(tag int) {
+	C.Mix_HaltGroup(C.int(tag))
+}
+
 // PlayChannel plays an audio chunk on a specific channel.  If the specified
 // channel is -1, play on the first free channel. If loops is greater then
 // zero, loop the sound that many times. If loops is -1, loop inifinitely.
 // Returns which channel was used to play the sound.
 func PlayChannel(channel int, chunk Chunk, loops int) (int, error) {
-	r := int(C.Mix_PlayChannelTimed(C.int(channel), chunk.ptr,
-		C.int(loops), -1))
-	if r == -1 {
-		return r, sdlError(r)
-	}
-	return r, nil
+	return PlayChannelTimed(channel, chunk, loops, -1)
 }
 
 // PlayChannelTimed is the same as PlayChannel, but the sound is played at
 // most ticks milliseconds.
 func PlayChannelTimed(channel int, chunk Chunk, loops, ticks int) (int, error) {
-	r := int(C.Mix_PlayChannelTimed(C.int(channel), chunk.ptr,
-		C.int(loops), C.int(ticks)))
-	if r == -1 {
-		return r, sdlError(r)
+	v, err := PlayVoiceTimed(channel, chunk, loops, ticks)
+	if err != nil {
+		return -1, err
 	}
-	return r, nil
+	return v.channel, nil
 }
 
 // Play plays m loops number of times.  If loops is -1 m will loop forever.
@@ -329,22 +439,16 @@ func (m Music) FadeInPos(loops, ms int, position float64) error {
 
 // Same as PlayChannel, but the chunk fades in over ms milliseconds.
 func FadeInChannel(channel int, chunk Chunk, loops, ms int) (int, error) {
-	r := int(C.Mix_FadeInChannelTimed(C.int(channel), chunk.ptr,
-		C.int(loops), C.int(ms), -1))
-	if r == -1 {
-		return r, sdlError(r)
-	}
-	return r, nil
+	return FadeInChannelTimed(channel, chunk, loops, ms, -1)
 }
 
 // Same as PlayChannelTimed, but the chunk fades in over ms milliseconds.
 func FadeInChannelTimed(channel int, chunk Chunk, loops, ms, ticks int) (int, error) {
-	r := int(C.Mix_FadeInChannelTimed(C.int(channel), chunk.ptr,
-		C.int(loops), C.int(ms), C.int(ticks)))
-	if r == -1 {
-		return r, sdlError(r)
+	v, err := FadeInVoiceTimed(channel, chunk, loops, ms, ticks)
+	if err != nil {
+		return -1, err
 	}
-	return r, nil
+	return v.channel, nil
 }
 
 // Volume sets the volume for any allocated channel.  If channel is -1 then all
@@ -359,6 +463,10 @@ func FadeInChannelTimed(channel int, chunk Chunk, loops, ms, ticks int) (int, er
 //
 // Volume returns the current volume of the channel.  If channel is -1, the
 // average volume is returned.
+//
+// Volume sets the volume directly.  The next SetChannelVolume or
+// Voice.SetVolume on the channel replaces it with the base volume times the
+// volume of the Voice.
 func Volume(channel, volume int) int {
 	return int(C.Mix_Volume(C.int(channel), C.int(volume)))
 }
@@ -385,6 +493,50 @@ func VolumeMusic(volume int) int {
 	return int(C.Mix_VolumeMusic(C.int(volume)))
 }
 
+// SetPanning sets the volume of the left and right speakers for channel, or
+// the final mix if channel is CHANNEL_POST, from 0 to 255.  255, 255 turns
+// panning off.  Panning is removed when the channel stops playing.
+func SetPanning(channel int, left, right uint8) error {
+	if C.Mix_SetPanning(C.int(channel), C.Uint8(left), C.Uint8(right)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+// SetDistance makes channel sound distance away, from 0 (near, full volume)
+// to 255 (far, quiet).  0 turns the effect off.  The distance is removed
+// when the channel stops playing.
+func SetDistance(channel int, distance uint8) error {
+	if C.Mix_SetDistance(C.int(channel), C.Uint8(distance)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+// SetPosition places channel at angle degrees around the listener, 0 is in
+// front, 90 to the right, 180 behind, and 270 to the left, and distance away
+// as in SetDistance.  0, 0 turns the effect off.  The position is removed when
+// the channel stops playing.
+func SetPosition(channel int, angle int16, distance uint8) error {
+	if C.Mix_SetPosition(C.int(channel), C.Sint16(angle), C.Uint8(distance)) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
+// SetReverseStereo swaps the left and right speakers of channel if flip is
+// true.  It is removed when the channel stops playing.
+func SetReverseStereo(channel int, flip bool) error {
+	var f C.int
+	if flip {
+		f = 1
+	}
+	if C.Mix_SetReverseStereo(C.int(channel), f) == 0 {
+		return sdlError(0)
+	}
+	return nil
+}
+
 // HaltChannel halts the playback on channel, or all channels if channel is -1.
 func HaltChannel(channel int) {
 	C.Mix_HaltChannel(C.int(channel))
@@ -0,0 +1,85 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "music.h"
+
+// The music information functions were added in SDL_mixer 2.6.0.  With older
+// headers the shims below are stubs, and musicInfoCompiled reports it.
+#if SDL_VERSIONNUM(SDL_MIXER_MAJOR_VERSION, SDL_MIXER_MINOR_VERSION, SDL_MIXER_PATCHLEVEL) >= SDL_VERSIONNUM(2, 6, 0)
+
+int musicInfoCompiled(void) {
+	return 1;
+}
+
+double musicDuration(Mix_Music *music) {
+	return Mix_MusicDuration(music);
+}
+
+double musicPosition(Mix_Music *music) {
+	return Mix_GetMusicPosition(music);
+}
+
+const char *musicTitle(Mix_Music *music) {
+	return Mix_GetMusicTitle(music);
+}
+
+const char *musicArtist(Mix_Music *music) {
+	return Mix_GetMusicArtistTag(music);
+}
+
+const char *musicAlbum(Mix_Music *music) {
+	return Mix_GetMusicAlbumTag(music);
+}
+
+double musicLoopStart(Mix_Music *music) {
+	return Mix_GetMusicLoopStartTime(music);
+}
+
+double musicLoopEnd(Mix_Music *music) {
+	return Mix_GetMusicLoopEndTime(music);
+}
+
+double musicLoopLength(Mix_Music *music) {
+	return Mix_GetMusicLoopLengthTime(music);
+}
+
+#else
+
+int musicInfoCompiled(void) {
+	return 0;
+}
+
+double musicDuration(Mix_Music *music) {
+	return -1;
+}
+
+double musicPosition(Mix_Music *music) {
+	return -1;
+}
+
+const char *musicTitle(Mix_Music *music) {
+	return "";
+}
+
+const char *musicArtist(Mix_Music *music) {
+	return "";
+}
+
+const char *musicAlbum(Mix_Music *music) {
+	return "";
+}
+
+double musicLoopStart(Mix_Music *music) {
+	return -1;
+}
+
+double musicLoopEnd(Mix_Music *music) {
+	return -1;
+}
+
+double musicLoopLength(Mix_Music *music) {
+	return -1;
+}
+
+#endif
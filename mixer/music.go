@@ -0,0 +1,115 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "music.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"errors"
+)
+
+// ErrUnsupported is returned by the Music methods that need a newer
+// SDL2_mixer than the one the program is built or linked with.
+var ErrUnsupported = errors.New("mixer: not supported by this version of SDL2_mixer")
+
+// musicInfo returns ErrUnsupported unless the music information functions of
+// SDL2_mixer 2.6.0 are available, both in the headers and in LinkedVersion.
+func musicInfo() error {
+	if C.musicInfoCompiled() == 0 {
+		return ErrUnsupported
+	}
+	v := LinkedVersion()
+	if sdl.VersionNum(int(v.Major), int(v.Minor), int(v.Patch)) < sdl.VersionNum(2, 6, 0) {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// musicTime returns the result of one of the music time shims.
+func musicTime(t C.double) (float64, error) {
+	if t < 0 {
+		return 0, sdlError(-1)
+	}
+	return float64(t), nil
+}
+
+// Duration returns the length of m in seconds, or of the currently playing
+// music, if m is a zero value Music.  Not all music formats know their
+// length.  It requires SDL2_mixer 2.6.0.
+func (m Music) Duration() (float64, error) {
+	if err := musicInfo(); err != nil {
+		return 0, err
+	}
+	return musicTime(C.musicDuration(m.ptr))
+}
+
+// Position returns the playing position of m in seconds, or of the currently
+// playing music, if m is a zero value Music.  It requires SDL2_mixer 2.6.0.
+func (m Music) Position() (float64, error) {
+	if err := musicInfo(); err != nil {
+		return 0, err
+	}
+	return musicTime(C.musicPosition(m.ptr))
+}
+
+// Title returns the title tag of m, or of the currently playing music, if m
+// is a zero value Music.  Music without a title tag returns its file name,
+// if it was loaded from a file.  It requires SDL2_mixer 2.6.0.
+func (m Music) Title() (string, error) {
+	if err := musicInfo(); err != nil {
+		return "", err
+	}
+	return C.GoString(C.musicTitle(m.ptr)), nil
+}
+
+// Artist returns the artist tag of m, or "" if it has none.  It requires
+// SDL2_mixer 2.6.0.
+func (m Music) Artist() (string, error) {
+	if err := musicInfo(); err != nil {
+		return "", err
+	}
+	return C.GoString(C.musicArtist(m.ptr)), nil
+}
+
+// Album returns the album tag of m, or "" if it has none.  It requires
+// SDL2_mixer 2.6.0.
+func (m Music) Album() (string, error) {
+	if err := musicInfo(); err != nil {
+		return "", err
+	}
+	return C.GoString(C.musicAlbum(m.ptr)), nil
+}
+
+// LoopStart returns the start of the loop of m in seconds, or -1 if m has
+// no loop points.  OGG files set their loop with the LOOPSTART and LOOPEND,
+// or LOOPLENGTH, tags, and when m loops it jumps from the end of the loop
+// back to its start instead of playing the whole file again.  It requires
+// SDL2_mixer 2.6.0.
+func (m Music) LoopStart() (float64, error) {
+	if err := musicInfo(); err != nil {
+		return 0, err
+	}
+	return float64(C.musicLoopStart(m.ptr)), nil
+}
+
+// LoopEnd returns the end of the loop of m in seconds, or -1 if m has no
+// loop points.  It requires SDL2_mixer 2.6.0.
+func (m Music) LoopEnd() (float64, error) {
+	if err := musicInfo(); err != nil {
+		return 0, err
+	}
+	return float64(C.musicLoopEnd(m.ptr)), nil
+}
+
+// LoopLength returns the length of the loop of m in seconds, or -1 if m has
+// no loop points.  It requires SDL2_mixer 2.6.0.
+func (m Music) LoopLength() (float64, error) {
+	if err := musicInfo(); err != nil {
+		return 0, err
+	}
+	return float64(C.musicLoopLength(m.ptr)), nil
+}
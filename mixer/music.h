@@ -0,0 +1,15 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL2/SDL_mixer.h"
+
+extern int musicInfoCompiled(void);
+extern double musicDuration(Mix_Music *music);
+extern double musicPosition(Mix_Music *music);
+extern const char *musicTitle(Mix_Music *music);
+extern const char *musicArtist(Mix_Music *music);
+extern const char *musicAlbum(Mix_Music *music);
+extern double musicLoopStart(Mix_Music *music);
+extern double musicLoopEnd(Mix_Music *music);
+extern double musicLoopLength(Mix_Music *music);
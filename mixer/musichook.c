@@ -0,0 +1,14 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "musichook.h"
+#include "_cgo_export.h"
+
+static void musicHook(void *udata, Uint8 *stream, int len) {
+	goMusicHook(stream, len);
+}
+
+void hookMusic(int on) {
+	Mix_HookMusic(on ? musicHook : NULL, NULL);
+}
@@ -0,0 +1,135 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "musichook.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"errors"
+	"io"
+	"sync/atomic"
+	"unsafe"
+)
+
+// openChunkSize is the chunksize passed to the last successful OpenAudio.
+var openChunkSize int
+
+// SampleGenerator generates music for HookMusic.
+//
+// Generate is called on the audio thread with the audio device locked, so it
+// must return quickly and must not call mixer functions.  It writes up to
+// len(samples.Bytes) bytes of audio, in the format of the audio device, to
+// samples and returns the number of bytes written.  The rest of the buffer
+// plays as silence and counts as an underrun.
+type SampleGenerator interface {
+	Generate(samples SampleBuffer) int
+}
+
+// SampleGeneratorFunc is a function that is a SampleGenerator.
+type SampleGeneratorFunc func(samples SampleBuffer) int
+
+// Generate calls f(samples).
+func (f SampleGeneratorFunc) Generate(samples SampleBuffer) int {
+	return f(samples)
+}
+
+// readerGenerator generates music by reading it from an io.Reader.
+type readerGenerator struct {
+	r io.Reader
+}
+
+func (g readerGenerator) Generate(samples SampleBuffer) int {
+	n, _ := io.ReadFull(g.r, samples.Bytes)
+	return n
+}
+
+// musicHookState is the generator installed by HookMusic and the format it
+// generates.  It is swapped atomically, because the audio thread reads it
+// with the audio device locked and HookMusic locks the device too.
+type musicHookState struct {
+	gen  SampleGenerator
+	spec SampleBuffer
+}
+
+var (
+	musicHook      atomic.Value // *musicHookState
+	musicUnderruns uint64       // Updated atomically
+	musicHookSize  int64        // Length of the last buffer, updated atomically
+)
+
+// HookMusic makes gen generate the music, instead of the music played with
+// Music.Play.  Sound effects are mixed on top of the generated music as
+// usual.  HookMusic replaces the generator of an earlier call.
+//
+// OpenAudio must be called before HookMusic.
+func HookMusic(gen SampleGenerator) error {
+	if gen == nil {
+		UnhookMusic()
+		return nil
+	}
+
+	format, frequency, channels, opened := QuerySpec()
+	if opened == 0 {
+		return errors.New("mixer: audio is not open")
+	}
+	musicHook.Store(&musicHookState{gen: gen, spec: SampleBuffer{
+		Format: format, Channels: channels, Frequency: frequency}})
+	C.hookMusic(1)
+	return nil
+}
+
+// HookMusicReader is the same as HookMusic, but the music is read from r.
+// r must return audio in the format of the audio device, convert it with an
+// sdl.AudioStream if needed.  When r returns less audio than the mixer asks
+// for, the rest is silence and counts as an underrun.
+func HookMusicReader(r io.Reader) error {
+	return HookMusic(readerGenerator{r})
+}
+
+// UnhookMusic removes the generator installed by HookMusic.  Once it returns
+// the generator is no longer called.
+func UnhookMusic() {
+	C.hookMusic(0)
+	musicHook.Store(&musicHookState{})
+}
+
+// MusicUnderruns returns the number of times the generator installed by
+// HookMusic returned less audio than the mixer asked for.
+func MusicUnderruns() uint64 {
+	return atomic.LoadUint64(&musicUnderruns)
+}
+
+// MusicHookBufferSize returns the number of bytes the mixer asks the
+// generator for each time.  Until the generator has been called once it is
+// estimated from the chunksize passed to OpenAudio.  A generator that renders
+// ahead, or a reader that buffers, should keep at least this much audio
+// ready.
+func MusicHookBufferSize() int {
+	if n := atomic.LoadInt64(&musicHookSize); n > 0 {
+		return int(n)
+	}
+	format, _, channels, opened := QuerySpec()
+	if opened == 0 {
+		return 0
+	}
+	return openChunkSize * channels * sdl.AudioFormat(format).BitSize() / 8
+}
+
+//export goMusicHook
+func goMusicHook(stream *C.Uint8, n C.int) {
+	atomic.StoreInt64(&musicHookSize, int64(n))
+	st, _ := musicHook.Load().(*musicHookState)
+	if st == nil || st.gen == nil {
+		return
+	}
+
+	buf := st.spec
+	buf.Bytes = byteSlice(unsafe.Pointer(stream), int(n))
+	if st.gen.Generate(buf) < int(n) {
+		atomic.AddUint64(&musicUnderruns, 1)
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL2/SDL_mixer.h"
+
+extern void hookMusic(int on);
@@ -0,0 +1,456 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package playlist plays a queue of music tracks one after another, with
+// shuffle, repeat modes, fades between tracks, and a gain for every track.
+//
+// Usage
+//
+// Create the playlist after mixer.OpenAudio, pass it every event, and call
+// Update once per frame:
+//
+//  list, err := playlist.New(2000)
+//  ...
+//  list.Add(title, 1)
+//  list.Add(battle, 0.8)
+//  list.SetRepeat(playlist.RepeatAll)
+//  list.Play()
+//  ...
+//  for event := sdl.PollEventTyped(); event != nil; event = sdl.PollEventTyped() {
+//  	list.HandleEvent(event)
+//  	if track, ok := list.TrackChanged(event); ok {
+//  		showTitle(track)
+//  	}
+//  }
+//  list.Update()
+//
+// The mixer plays one music at a time, so the fade between two tracks is a
+// fade out followed by a fade in.  The fade out starts early enough for the
+// track to end when it completes if the linked SDL2_mixer can tell the
+// length of the music, see mixer.Music.Duration.
+//
+// A playlist owns the music: it installs the music finished hook and sets
+// the music volume, and music played or halted by anything else confuses it.
+// To combine the playlist with the music volume and ducking of package bus,
+// let the buses set the volume:
+//
+//  list.SetGainFunc(buses.SetMusicGain)
+package playlist
+
+import (
+	"grate/backend/sdl2"
+	"grate/backend/sdl2/mixer"
+	"errors"
+	"math/rand"
+)
+
+const maxVolume = 128
+
+// startOver is the position in order that starts over from the first track
+// with RepeatAll.  A shuffled playlist is shuffled again when it is played.
+const startOver = -2
+
+// Repeat says what happens when a track ends.
+type Repeat int
+
+const (
+	RepeatOff Repeat = iota // Play the next track, stop after the last one
+	RepeatAll               // Play the next track, start over after the last one
+	RepeatOne               // Play the same track again
+)
+
+// Track is a track of a playlist.
+type Track struct {
+	Music mixer.Music
+	Gain  float64 // Volume of the track, from 0 to 1
+}
+
+// Playlist is a queue of tracks.
+type Playlist struct {
+	tracks    []Track
+	order     []int // Indices of tracks in play order
+	current   int   // Position in order of the playing track, or -1
+	repeat    Repeat
+	shuffle   bool
+	crossfade int // Milliseconds
+	volume    float64
+	gainFunc  func(gain float64)
+
+	// While the music fades out, or halts, the playlist waits for the
+	// music finished event and then plays pending, a position in order or
+	// startOver, or stops if pending is -1.
+	waiting bool
+	pending int
+
+	changedType sdl.EventType
+}
+
+// New returns an empty playlist that fades between tracks over crossfade
+// milliseconds, 0 switches tracks right away.  It installs the music
+// finished hook with mixer.HookMusicFinished.
+func New(crossfade int) (*Playlist, error) {
+	if _, err := mixer.HookMusicFinished(); err != nil {
+		return nil, err
+	}
+	t := sdl.RegisterEvents(1)
+	if t == 0xFFFFFFFF {
+		return nil, errors.New("playlist: no user event types left")
+	}
+	return &Playlist{
+		current:     -1,
+		crossfade:   crossfade,
+		volume:      1,
+		changedType: t,
+	}, nil
+}
+
+// Add appends music to p and returns the index of its track.  gain is the
+// volume of the track, from 0 to 1.  When p is shuffled the track plays
+// after the tracks already queued.
+func (p *Playlist) Add(music mixer.Music, gain float64) int {
+	p.tracks = append(p.tracks, Track{Music: music, Gain: clamp(gain)})
+	p.order = append(p.order, len(p.tracks)-1)
+	return len(p.tracks) - 1
+}
+
+// Len returns the number of tracks in p.
+func (p *Playlist) Len() int {
+	return len(p.tracks)
+}
+
+// Track returns track i of p.
+func (p *Playlist) Track(i int) Track {
+	return p.tracks[i]
+}
+
+// Clear stops the music and removes all tracks from p.  The music of the
+// tracks is not freed.
+func (p *Playlist) Clear() {
+	p.Stop()
+	p.tracks = nil
+	p.order = nil
+	p.current = -1
+}
+
+// SetCrossfade sets the time, in milliseconds, to fade between tracks.
+func (p *Playlist) SetCrossfade(ms int) {
+	if ms < 0 {
+		ms = 0
+	}
+	p.crossfade = ms
+}
+
+// Crossfade returns the time to fade between tracks.
+func (p *Playlist) Crossfade() int {
+	return p.crossfade
+}
+
+// SetRepeat sets what happens when a track ends.
+func (p *Playlist) SetRepeat(repeat Repeat) {
+	p.repeat = repeat
+}
+
+// Repeat returns what happens when a track ends.
+func (p *Playlist) Repeat() Repeat {
+	return p.repeat
+}
+
+// SetShuffle turns shuffling on or off.  Turning it on shuffles the tracks,
+// and with RepeatAll they are shuffled again each time the playlist starts
+// over.  The playing track keeps playing.
+func (p *Playlist) SetShuffle(shuffle bool) {
+	p.shuffle = shuffle
+	p.reorder()
+}
+
+// Shuffled returns true if p is shuffled.
+func (p *Playlist) Shuffled() bool {
+	return p.shuffle
+}
+
+// SetVolume sets the volume of p, from 0 to 1.  The music plays at the
+// volume of p times the gain of the track.
+func (p *Playlist) SetVolume(volume float64) {
+	p.volume = clamp(volume)
+	if p.current >= 0 {
+		p.applyVolume()
+	}
+}
+
+// Volume returns the volume of p.
+func (p *Playlist) Volume() float64 {
+	return p.volume
+}
+
+// SetGainFunc makes p call f with the volume of the playing track, the volume
+// of p times the gain of the track from 0 to 1, instead of setting the music
+// volume itself.  Use it when something else, such as package bus, also sets
+// the music volume.  A nil f makes p set the music volume again.
+func (p *Playlist) SetGainFunc(f func(gain float64)) {
+	p.gainFunc = f
+	if p.current >= 0 {
+		p.applyVolume()
+	}
+}
+
+// Current returns the index of the playing track, or -1 if p is stopped.
+// While fading to another track it is still the track that fades out.
+func (p *Playlist) Current() int {
+	if p.current < 0 {
+		return -1
+	}
+	return p.order[p.current]
+}
+
+// Play starts playing p from the first track.  A shuffled playlist is
+// shuffled again first.
+func (p *Playlist) Play() error {
+	if len(p.tracks) == 0 {
+		return errors.New("playlist: no tracks")
+	}
+	p.current = -1
+	p.reorder()
+	return p.change(0)
+}
+
+// PlayTrack plays track i, fading out the playing track.
+func (p *Playlist) PlayTrack(i int) error {
+	if i < 0 || i >= len(p.tracks) {
+		return errors.New("playlist: no such track")
+	}
+	for pos, t := range p.order {
+		if t == i {
+			return p.change(pos)
+		}
+	}
+	return nil
+}
+
+// Next fades to the next track.  After the last track p starts over with
+// RepeatAll and stops otherwise.
+func (p *Playlist) Next() error {
+	return p.change(p.step(1))
+}
+
+// Previous fades to the previous track.  Before the first track p goes to
+// the last one with RepeatAll and stops otherwise.
+func (p *Playlist) Previous() error {
+	return p.change(p.step(-1))
+}
+
+// Stop fades out the music and stops p.
+func (p *Playlist) Stop() {
+	p.change(-1)
+}
+
+// HandleEvent moves p to the next track when event is the music finished
+// event.  Pass it every event.  It returns an error if the next track could
+// not be played, p is stopped then.
+func (p *Playlist) HandleEvent(event sdl.Event) error {
+	if !mixer.MusicFinished(event) {
+		return nil
+	}
+	if !p.waiting {
+		if p.current < 0 {
+			return nil
+		}
+		p.pending = p.following()
+	}
+	p.waiting = false
+	return p.start(p.pending)
+}
+
+// Update starts fading out the playing track when it is about to end, so the
+// next track fades in on time.  Call it once per frame.  It does nothing if
+// the linked SDL2_mixer can not tell the length of the music.
+func (p *Playlist) Update() {
+	if p.current < 0 || p.waiting || p.crossfade == 0 {
+		return
+	}
+
+	music := p.tracks[p.order[p.current]].Music
+	duration, err := music.Duration()
+	if err != nil {
+		return
+	}
+	position, err := music.Position()
+	if err != nil {
+		return
+	}
+	if duration-position > float64(p.crossfade)/1000 {
+		return
+	}
+
+	if mixer.FadeOutMusic(p.crossfade) == nil {
+		p.pending = p.following()
+		p.waiting = true
+	}
+}
+
+// TrackChanged returns the index of the track that started if event was
+// pushed by p when it moved to another track, or -1 if p stopped.  ok is
+// false for all other events.
+func (p *Playlist) TrackChanged(event sdl.Event) (track int, ok bool) {
+	var e *sdl.UserEvent
+	switch ev := event.(type) {
+	case *sdl.UserEvent:
+		e = ev
+	case *sdl.EventUnion:
+		e, _ = ev.Convert().(*sdl.UserEvent)
+	}
+	if e == nil || e.Type != p.changedType {
+		return 0, false
+	}
+	return int(e.Code), true
+}
+
+// change moves p to position pos of order or startOver, or stops it if pos
+// is -1.  The playing track fades out first and the move happens in
+// HandleEvent.
+func (p *Playlist) change(pos int) error {
+	if pos == -1 && p.current < 0 && !p.waiting {
+		return nil
+	}
+	if p.waiting {
+		p.pending = pos
+		return nil
+	}
+	if !mixer.PlayingMusic() {
+		return p.start(pos)
+	}
+
+	p.pending = pos
+	p.waiting = true
+	if p.crossfade == 0 || mixer.FadeOutMusic(p.crossfade) != nil {
+		mixer.HaltMusic()
+	}
+	return nil
+}
+
+// start plays position pos of order or startOver, or stops p if pos is -1,
+// and pushes the track changed event.
+func (p *Playlist) start(pos int) error {
+	if pos == startOver {
+		p.reshuffle()
+		pos = 0
+	}
+
+	var err error
+	if pos >= 0 && pos < len(p.order) {
+		p.current = pos
+		p.applyVolume()
+		err = p.tracks[p.order[pos]].Music.FadeInPos(1, p.crossfade, 0)
+	} else {
+		pos = -1
+	}
+	if pos < 0 || err != nil {
+		p.current = -1
+	}
+	p.pushChanged()
+	return err
+}
+
+// following returns the position in order to play when the playing track
+// ends, startOver, or -1 to stop.
+func (p *Playlist) following() int {
+	if p.repeat == RepeatOne {
+		return p.current
+	}
+	return p.step(1)
+}
+
+// step returns the position in order n tracks away from the playing track,
+// startOver when going past the last track with RepeatAll, or -1 to stop.
+func (p *Playlist) step(n int) int {
+	if len(p.order) == 0 {
+		return -1
+	}
+	pos := p.current + n
+	if p.current < 0 {
+		pos = 0
+	}
+	if pos >= 0 && pos < len(p.order) {
+		return pos
+	}
+	if p.repeat != RepeatAll {
+		return -1
+	}
+	if pos >= len(p.order) {
+		return startOver
+	}
+	return len(p.order) - 1
+}
+
+// reshuffle shuffles a shuffled playlist again for the next round of
+// RepeatAll.  The track that just played goes last, so it does not play twice
+// in a row.
+func (p *Playlist) reshuffle() {
+	if !p.shuffle {
+		return
+	}
+	last := p.Current()
+	p.order = rand.Perm(len(p.tracks))
+	for pos, t := range p.order {
+		if t == last {
+			end := len(p.order) - 1
+			p.order[pos], p.order[end] = p.order[end], p.order[pos]
+			break
+		}
+	}
+}
+
+// reorder sets the play order for the shuffle setting.  The playing track is
+// moved to the front, or keeps its place when not shuffled.
+func (p *Playlist) reorder() {
+	playing := p.Current()
+	if p.shuffle {
+		p.order = rand.Perm(len(p.tracks))
+	} else {
+		for i := range p.order {
+			p.order[i] = i
+		}
+	}
+	if playing < 0 {
+		return
+	}
+	for pos, t := range p.order {
+		if t == playing {
+			if p.shuffle {
+				p.order[0], p.order[pos] = p.order[pos], p.order[0]
+				pos = 0
+			}
+			p.current = pos
+			break
+		}
+	}
+}
+
+func (p *Playlist) applyVolume() {
+	gain := p.volume * p.tracks[p.order[p.current]].Gain
+	if p.gainFunc != nil {
+		p.gainFunc(gain)
+		return
+	}
+	mixer.VolumeMusic(int(gain * maxVolume))
+}
+
+// pushChanged pushes the track changed event.  It is a plain UserEvent, so
+// an event that is never read holds no Go memory.
+func (p *Playlist) pushChanged() {
+	ev := sdl.UserEvent{Type: p.changedType, Code: int32(p.Current())}
+	var evu sdl.EventUnion
+	if sdl.CopyEventToEventUnion(&ev, &evu) == nil {
+		sdl.PushEvent(&evu)
+	}
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package playlist
+
+import (
+	"grate/backend/sdl2/mixer"
+	"reflect"
+	"testing"
+)
+
+// newTestList returns a stopped playlist of n tracks, without touching the
+// mixer.
+func newTestList(n int, repeat Repeat) *Playlist {
+	p := &Playlist{current: -1, volume: 1, repeat: repeat}
+	for i := 0; i < n; i++ {
+		p.Add(mixer.Music{}, 1)
+	}
+	return p
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		repeat  Repeat
+		current int
+		n       int
+		want    int
+	}{
+		{RepeatOff, -1, 1, 0},
+		{RepeatOff, 0, 1, 1},
+		{RepeatOff, 2, 1, -1},
+		{RepeatOff, 1, -1, 0},
+		{RepeatOff, 0, -1, -1}, // Previous at the first track stops
+		{RepeatAll, 2, 1, startOver},
+		{RepeatAll, 0, -1, 2}, // Previous at the first track goes to the last
+		{RepeatOne, 1, 1, 2},
+		{RepeatOne, 2, 1, -1},
+	}
+	for _, test := range tests {
+		p := newTestList(3, test.repeat)
+		p.current = test.current
+		if got := p.step(test.n); got != test.want {
+			t.Errorf("repeat %d, current %d, step(%d): got %d, want %d",
+				test.repeat, test.current, test.n, got, test.want)
+		}
+	}
+
+	if got := newTestList(0, RepeatAll).step(1); got != -1 {
+		t.Errorf("empty playlist: got %d, want -1", got)
+	}
+}
+
+func TestFollowing(t *testing.T) {
+	tests := []struct {
+		repeat  Repeat
+		current int
+		want    int
+	}{
+		{RepeatOff, 0, 1},
+		{RepeatOff, 2, -1},
+		{RepeatAll, 2, startOver},
+		{RepeatOne, 2, 2},
+	}
+	for _, test := range tests {
+		p := newTestList(3, test.repeat)
+		p.SetShuffle(true)
+		p.current = test.current
+		order := append([]int(nil), p.order...)
+
+		if got := p.following(); got != test.want {
+			t.Errorf("repeat %d, current %d: got %d, want %d",
+				test.repeat, test.current, got, test.want)
+		}
+		// Looking ahead, as Update does, must not change the order.
+		if !reflect.DeepEqual(p.order, order) || p.current != test.current {
+			t.Errorf("repeat %d: following changed order %v to %v, current %d to %d",
+				test.repeat, order, p.order, test.current, p.current)
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	p := newTestList(5, RepeatAll)
+	p.current = 3
+	p.SetShuffle(true)
+	if !p.Shuffled() {
+		t.Fatal("not shuffled")
+	}
+	if p.current != 0 || p.Current() != 3 {
+		t.Errorf("playing track moved: current %d, track %d, want 0, 3", p.current, p.Current())
+	}
+	checkPerm(t, p.order)
+
+	p.SetShuffle(false)
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(p.order, want) {
+		t.Errorf("unshuffled order %v, want %v", p.order, want)
+	}
+	if p.current != 3 {
+		t.Errorf("unshuffled current %d, want 3", p.current)
+	}
+}
+
+func TestReshuffle(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		p := newTestList(4, RepeatAll)
+		p.SetShuffle(true)
+		p.current = len(p.order) - 1
+		last := p.Current()
+
+		p.reshuffle()
+		checkPerm(t, p.order)
+		// The track that just played does not play again right away.
+		if p.order[len(p.order)-1] != last {
+			t.Fatalf("order %v: track %d that just played is not last", p.order, last)
+		}
+	}
+
+	p := newTestList(4, RepeatAll)
+	p.current = 3
+	p.reshuffle()
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(p.order, want) {
+		t.Errorf("reshuffle changed the order of an unshuffled playlist to %v", p.order)
+	}
+}
+
+// checkPerm reports order if it is not a permutation of the tracks.
+func checkPerm(t *testing.T, order []int) {
+	t.Helper()
+	seen := make(map[int]bool)
+	for _, track := range order {
+		if track < 0 || track >= len(order) || seen[track] {
+			t.Errorf("order %v is not a permutation", order)
+			return
+		}
+		seen[track] = true
+	}
+}
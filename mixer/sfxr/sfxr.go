@@ -0,0 +1,277 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sfxr synthesizes retro game sound effects from a small set of
+// parameters, following the sfxr generator by Tomas Pettersson.
+//
+// Usage
+//
+// Start from one of the generators and turn the parameters into a chunk:
+//
+//  r := rand.New(rand.NewSource(seed))
+//  p := sfxr.PickupCoin(r)
+//  coin, err := p.Chunk()
+//  ...
+//  mixer.PlayChannel(-1, coin, 0)
+//
+// Parameters range from 0 to 1, or from -1 to 1 for the ones that slide or
+// ramp.  The same parameters always give the same sound, so a game can keep
+// the parameters instead of audio files.
+package sfxr
+
+import (
+	"grate/backend/sdl2/mixer"
+	"math/rand"
+)
+
+// SampleRate is the rate, in frames per second, of the samples Synthesize
+// returns.
+const SampleRate = 44100
+
+// Wave is the shape of the basic sound.
+type Wave int
+
+const (
+	Square Wave = iota
+	Sawtooth
+	Sine
+	Noise
+)
+
+// Params describes a sound effect.
+type Params struct {
+	Wave Wave
+
+	BaseFreq  float64 // Start frequency
+	FreqLimit float64 // The sound stops when it slides below this frequency
+	FreqRamp  float64 // Slide, -1 to 1
+	FreqDramp float64 // Change of slide, -1 to 1
+
+	Duty     float64 // Square wave duty cycle
+	DutyRamp float64 // Duty sweep, -1 to 1
+
+	VibStrength float64 // Vibrato depth
+	VibSpeed    float64 // Vibrato speed
+
+	EnvAttack  float64 // Attack time
+	EnvSustain float64 // Sustain time
+	EnvPunch   float64 // Sustain punch
+	EnvDecay   float64 // Decay time
+
+	LPFFreq      float64 // Low-pass filter cutoff, 1 is off
+	LPFRamp      float64 // Low-pass cutoff sweep, -1 to 1
+	LPFResonance float64 // Low-pass resonance
+	HPFFreq      float64 // High-pass filter cutoff, 0 is off
+	HPFRamp      float64 // High-pass cutoff sweep, -1 to 1
+
+	PhaserOffset float64 // Phaser offset, -1 to 1
+	PhaserRamp   float64 // Phaser sweep, -1 to 1
+
+	RepeatSpeed float64 // Restarts the slides and arpeggio, 0 is off
+
+	ArpSpeed float64 // Time until the frequency jumps
+	ArpMod   float64 // Frequency jump, -1 to 1
+
+	Volume float64 // Volume of the sound, 0.5 is the sfxr default
+}
+
+// Default returns the parameters of a plain square wave beep.
+func Default() Params {
+	return Params{
+		BaseFreq:   0.3,
+		EnvSustain: 0.3,
+		EnvDecay:   0.4,
+		LPFFreq:    1,
+		Volume:     0.5,
+	}
+}
+
+// frnd returns a random number from 0 to max.
+func frnd(r *rand.Rand, max float64) float64 {
+	return r.Float64() * max
+}
+
+// rnd returns a random integer from 0 to max.
+func rnd(r *rand.Rand, max int) int {
+	return r.Intn(max + 1)
+}
+
+// PickupCoin returns random parameters for a coin pickup sound.
+func PickupCoin(r *rand.Rand) Params {
+	p := Default()
+	p.BaseFreq = 0.4 + frnd(r, 0.5)
+	p.EnvAttack = 0
+	p.EnvSustain = frnd(r, 0.1)
+	p.EnvDecay = 0.1 + frnd(r, 0.4)
+	p.EnvPunch = 0.3 + frnd(r, 0.3)
+	if rnd(r, 1) == 1 {
+		p.ArpSpeed = 0.5 + frnd(r, 0.2)
+		p.ArpMod = 0.2 + frnd(r, 0.4)
+	}
+	return p
+}
+
+// LaserShoot returns random parameters for a laser shot.
+func LaserShoot(r *rand.Rand) Params {
+	p := Default()
+	p.Wave = Wave(rnd(r, 2))
+	if p.Wave == Sine && rnd(r, 1) == 1 {
+		p.Wave = Wave(rnd(r, 1))
+	}
+	p.BaseFreq = 0.5 + frnd(r, 0.5)
+	p.FreqLimit = p.BaseFreq - 0.2 - frnd(r, 0.6)
+	if p.FreqLimit < 0.2 {
+		p.FreqLimit = 0.2
+	}
+	p.FreqRamp = -0.15 - frnd(r, 0.2)
+	if rnd(r, 2) == 0 {
+		p.BaseFreq = 0.3 + frnd(r, 0.6)
+		p.FreqLimit = frnd(r, 0.1)
+		p.FreqRamp = -0.35 - frnd(r, 0.3)
+	}
+	if rnd(r, 1) == 1 {
+		p.Duty = frnd(r, 0.5)
+		p.DutyRamp = frnd(r, 0.2)
+	} else {
+		p.Duty = 0.4 + frnd(r, 0.5)
+		p.DutyRamp = -frnd(r, 0.7)
+	}
+	p.EnvAttack = 0
+	p.EnvSustain = 0.1 + frnd(r, 0.2)
+	p.EnvDecay = frnd(r, 0.4)
+	if rnd(r, 1) == 1 {
+		p.EnvPunch = frnd(r, 0.3)
+	}
+	if rnd(r, 2) == 0 {
+		p.PhaserOffset = frnd(r, 0.2)
+		p.PhaserRamp = -frnd(r, 0.2)
+	}
+	if rnd(r, 1) == 1 {
+		p.HPFFreq = frnd(r, 0.3)
+	}
+	return p
+}
+
+// Explosion returns random parameters for an explosion.
+func Explosion(r *rand.Rand) Params {
+	p := Default()
+	p.Wave = Noise
+	if rnd(r, 1) == 1 {
+		p.BaseFreq = 0.1 + frnd(r, 0.4)
+		p.FreqRamp = -0.1 + frnd(r, 0.4)
+	} else {
+		p.BaseFreq = 0.2 + frnd(r, 0.7)
+		p.FreqRamp = -0.2 - frnd(r, 0.2)
+	}
+	p.BaseFreq *= p.BaseFreq
+	if rnd(r, 4) == 0 {
+		p.FreqRamp = 0
+	}
+	if rnd(r, 2) == 0 {
+		p.RepeatSpeed = 0.3 + frnd(r, 0.5)
+	}
+	p.EnvAttack = 0
+	p.EnvSustain = 0.1 + frnd(r, 0.3)
+	p.EnvDecay = frnd(r, 0.5)
+	if rnd(r, 1) == 0 {
+		p.PhaserOffset = -0.3 + frnd(r, 0.9)
+		p.PhaserRamp = -frnd(r, 0.3)
+	}
+	p.EnvPunch = 0.2 + frnd(r, 0.6)
+	if rnd(r, 1) == 1 {
+		p.VibStrength = frnd(r, 0.7)
+		p.VibSpeed = frnd(r, 0.6)
+	}
+	if rnd(r, 2) == 0 {
+		p.ArpSpeed = 0.6 + frnd(r, 0.3)
+		p.ArpMod = 0.8 - frnd(r, 1.6)
+	}
+	return p
+}
+
+// PowerUp returns random parameters for a power up.
+func PowerUp(r *rand.Rand) Params {
+	p := Default()
+	if rnd(r, 1) == 1 {
+		p.Wave = Sawtooth
+	} else {
+		p.Duty = frnd(r, 0.6)
+	}
+	if rnd(r, 1) == 1 {
+		p.BaseFreq = 0.2 + frnd(r, 0.3)
+		p.FreqRamp = 0.1 + frnd(r, 0.4)
+		p.RepeatSpeed = 0.4 + frnd(r, 0.4)
+	} else {
+		p.BaseFreq = 0.2 + frnd(r, 0.3)
+		p.FreqRamp = 0.05 + frnd(r, 0.2)
+		if rnd(r, 1) == 1 {
+			p.VibStrength = frnd(r, 0.7)
+			p.VibSpeed = frnd(r, 0.6)
+		}
+	}
+	p.EnvAttack = 0
+	p.EnvSustain = frnd(r, 0.4)
+	p.EnvDecay = 0.1 + frnd(r, 0.4)
+	return p
+}
+
+// HitHurt returns random parameters for a hit.
+func HitHurt(r *rand.Rand) Params {
+	p := Default()
+	p.Wave = Wave(rnd(r, 2))
+	if p.Wave == Sine {
+		p.Wave = Noise
+	}
+	if p.Wave == Square {
+		p.Duty = frnd(r, 0.6)
+	}
+	p.BaseFreq = 0.2 + frnd(r, 0.6)
+	p.FreqRamp = -0.3 - frnd(r, 0.4)
+	p.EnvAttack = 0
+	p.EnvSustain = frnd(r, 0.1)
+	p.EnvDecay = 0.1 + frnd(r, 0.2)
+	if rnd(r, 1) == 1 {
+		p.HPFFreq = frnd(r, 0.3)
+	}
+	return p
+}
+
+// Jump returns random parameters for a jump.
+func Jump(r *rand.Rand) Params {
+	p := Default()
+	p.Wave = Square
+	p.Duty = frnd(r, 0.6)
+	p.BaseFreq = 0.3 + frnd(r, 0.3)
+	p.FreqRamp = 0.1 + frnd(r, 0.2)
+	p.EnvAttack = 0
+	p.EnvSustain = 0.1 + frnd(r, 0.3)
+	p.EnvDecay = 0.1 + frnd(r, 0.2)
+	if rnd(r, 1) == 1 {
+		p.HPFFreq = frnd(r, 0.3)
+	}
+	if rnd(r, 1) == 1 {
+		p.LPFFreq = 1 - frnd(r, 0.6)
+	}
+	return p
+}
+
+// BlipSelect returns random parameters for a menu blip.
+func BlipSelect(r *rand.Rand) Params {
+	p := Default()
+	p.Wave = Wave(rnd(r, 1))
+	if p.Wave == Square {
+		p.Duty = frnd(r, 0.6)
+	}
+	p.BaseFreq = 0.2 + frnd(r, 0.4)
+	p.EnvAttack = 0
+	p.EnvSustain = 0.1 + frnd(r, 0.1)
+	p.EnvDecay = frnd(r, 0.2)
+	p.HPFFreq = 0.1
+	return p
+}
+
+// Chunk synthesizes p into a mixer chunk.  OpenAudio must be called first.
+func (p *Params) Chunk() (mixer.Chunk, error) {
+	return mixer.NewChunkFromSamples(p.Synthesize(), 1, SampleRate)
+}
@@ -0,0 +1,69 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfxr
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkSamples reports samples that are not finite or outside -1 to 1, and
+// sounds that are empty or silent.
+func checkSamples(t *testing.T, name string, out []float32) {
+	t.Helper()
+	if len(out) == 0 {
+		t.Errorf("%s: no samples", name)
+		return
+	}
+	loud := false
+	for i, v := range out {
+		if math.IsNaN(float64(v)) || v < -1 || v > 1 {
+			t.Errorf("%s: sample %d is %v, want -1 to 1", name, i, v)
+			return
+		}
+		loud = loud || v != 0
+	}
+	if !loud {
+		t.Errorf("%s: all %d samples are silent", name, len(out))
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	p := Default()
+	out := p.Synthesize()
+	// No attack, 0.3*0.3*100000 frames of sustain and 0.4*0.4*100000 of
+	// decay, plus the first frame of each of the two stages.
+	if len(out) != 25002 {
+		t.Errorf("got %d samples, want 25002", len(out))
+	}
+	checkSamples(t, "Default", out)
+
+	if again := p.Synthesize(); !reflect.DeepEqual(again, out) {
+		t.Error("the same parameters gave a different sound")
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	generators := []struct {
+		name string
+		gen  func(*rand.Rand) Params
+	}{
+		{"PickupCoin", PickupCoin},
+		{"LaserShoot", LaserShoot},
+		{"Explosion", Explosion},
+		{"PowerUp", PowerUp},
+		{"HitHurt", HitHurt},
+		{"Jump", Jump},
+		{"BlipSelect", BlipSelect},
+	}
+	for _, g := range generators {
+		for seed := int64(1); seed <= 20; seed++ {
+			p := g.gen(rand.New(rand.NewSource(seed)))
+			checkSamples(t, g.name, p.Synthesize())
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfxr
+
+import (
+	"math"
+	"math/rand"
+)
+
+const masterVolume = 0.05
+
+// synth holds the state of the generator while it runs.
+type synth struct {
+	p     *Params
+	noise *rand.Rand
+
+	playing bool
+	phase   int
+	fperiod float64
+	fmaxper float64
+	fslide  float64
+	fdslide float64
+	period  int
+
+	squareDuty  float64
+	squareSlide float64
+
+	envStage  int
+	envTime   int
+	envLength [3]int
+	envVol    float64
+
+	fphase  float64
+	fdphase float64
+	iphase  int
+	phaser  [1024]float64
+	ipp     int
+
+	noiseBuf [32]float64
+
+	fltp, fltdp, fltw, fltwd, fltdmp, fltphp, flthp, flthpd float64
+
+	vibPhase, vibSpeed, vibAmp float64
+
+	repTime, repLimit int
+	arpTime, arpLimit int
+	arpMod            float64
+}
+
+// reset starts the sound, or restarts the slides and arpeggio when restart
+// is true.
+func (s *synth) reset(restart bool) {
+	p := s.p
+	if !restart {
+		s.phase = 0
+	}
+	s.fperiod = 100 / (p.BaseFreq*p.BaseFreq + 0.001)
+	s.period = int(s.fperiod)
+	s.fmaxper = 100 / (p.FreqLimit*p.FreqLimit + 0.001)
+	s.fslide = 1 - math.Pow(p.FreqRamp, 3)*0.01
+	s.fdslide = -math.Pow(p.FreqDramp, 3) * 0.000001
+	s.squareDuty = 0.5 - p.Duty*0.5
+	s.squareSlide = -p.DutyRamp * 0.00005
+	if p.ArpMod >= 0 {
+		s.arpMod = 1 - p.ArpMod*p.ArpMod*0.9
+	} else {
+		s.arpMod = 1 + p.ArpMod*p.ArpMod*10
+	}
+	s.arpTime = 0
+	s.arpLimit = int(math.Pow(1-p.ArpSpeed, 2)*20000 + 32)
+	if p.ArpSpeed == 1 {
+		s.arpLimit = 0
+	}
+	if restart {
+		return
+	}
+
+	s.fltp, s.fltdp = 0, 0
+	s.fltw = math.Pow(p.LPFFreq, 3) * 0.1
+	s.fltwd = 1 + p.LPFRamp*0.0001
+	s.fltdmp = 5 / (1 + p.LPFResonance*p.LPFResonance*20) * (0.01 + s.fltw)
+	if s.fltdmp > 0.8 {
+		s.fltdmp = 0.8
+	}
+	s.fltphp = 0
+	s.flthp = p.HPFFreq * p.HPFFreq * 0.1
+	s.flthpd = 1 + p.HPFRamp*0.0003
+
+	s.vibPhase = 0
+	s.vibSpeed = p.VibSpeed * p.VibSpeed * 0.01
+	s.vibAmp = p.VibStrength * 0.5
+
+	s.envVol = 0
+	s.envStage = 0
+	s.envTime = 0
+	s.envLength[0] = int(p.EnvAttack * p.EnvAttack * 100000)
+	s.envLength[1] = int(p.EnvSustain * p.EnvSustain * 100000)
+	s.envLength[2] = int(p.EnvDecay * p.EnvDecay * 100000)
+
+	s.fphase = p.PhaserOffset * p.PhaserOffset * 1020
+	if p.PhaserOffset < 0 {
+		s.fphase = -s.fphase
+	}
+	s.fdphase = p.PhaserRamp * p.PhaserRamp
+	if p.PhaserRamp < 0 {
+		s.fdphase = -s.fdphase
+	}
+	s.iphase = abs(int(s.fphase))
+	s.ipp = 0
+	s.phaser = [1024]float64{}
+	s.fillNoise()
+
+	s.repTime = 0
+	s.repLimit = int(math.Pow(1-p.RepeatSpeed, 2)*20000 + 32)
+	if p.RepeatSpeed == 0 {
+		s.repLimit = 0
+	}
+}
+
+func (s *synth) fillNoise() {
+	for i := range s.noiseBuf {
+		s.noiseBuf[i] = s.noise.Float64()*2 - 1
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// fraction returns t/length, or 1 when the stage has no length.
+func fraction(t, length int) float64 {
+	if length == 0 {
+		return 1
+	}
+	return float64(t) / float64(length)
+}
+
+// sample returns the next sample frame.
+func (s *synth) sample() float64 {
+	p := s.p
+
+	s.repTime++
+	if s.repLimit != 0 && s.repTime >= s.repLimit {
+		s.repTime = 0
+		s.reset(true)
+	}
+
+	// Frequency envelopes and arpeggios.
+	s.arpTime++
+	if s.arpLimit != 0 && s.arpTime >= s.arpLimit {
+		s.arpLimit = 0
+		s.fperiod *= s.arpMod
+	}
+	s.fslide += s.fdslide
+	s.fperiod *= s.fslide
+	if s.fperiod > s.fmaxper {
+		s.fperiod = s.fmaxper
+		if p.FreqLimit > 0 {
+			s.playing = false
+		}
+	}
+	rfperiod := s.fperiod
+	if s.vibAmp > 0 {
+		s.vibPhase += s.vibSpeed
+		rfperiod = s.fperiod * (1 + math.Sin(s.vibPhase)*s.vibAmp)
+	}
+	s.period = int(rfperiod)
+	if s.period < 8 {
+		s.period = 8
+	}
+	s.squareDuty += s.squareSlide
+	if s.squareDuty < 0 {
+		s.squareDuty = 0
+	}
+	if s.squareDuty > 0.5 {
+		s.squareDuty = 0.5
+	}
+
+	// Volume envelope.
+	s.envTime++
+	if s.envTime > s.envLength[s.envStage] {
+		s.envTime = 0
+		s.envStage++
+		if s.envStage == 3 {
+			s.playing = false
+			return 0
+		}
+	}
+	switch s.envStage {
+	case 0:
+		s.envVol = fraction(s.envTime, s.envLength[0])
+	case 1:
+		s.envVol = 1 + (1-fraction(s.envTime, s.envLength[1]))*2*p.EnvPunch
+	case 2:
+		s.envVol = 1 - fraction(s.envTime, s.envLength[2])
+	}
+
+	// Phaser step.
+	s.fphase += s.fdphase
+	s.iphase = abs(int(s.fphase))
+	if s.iphase > 1023 {
+		s.iphase = 1023
+	}
+
+	if s.flthpd != 0 {
+		s.flthp *= s.flthpd
+		if s.flthp < 0.00001 {
+			s.flthp = 0.00001
+		}
+		if s.flthp > 0.1 {
+			s.flthp = 0.1
+		}
+	}
+
+	// 8x supersampling.
+	var ssample float64
+	for si := 0; si < 8; si++ {
+		s.phase++
+		if s.phase >= s.period {
+			s.phase %= s.period
+			if p.Wave == Noise {
+				s.fillNoise()
+			}
+		}
+		fp := float64(s.phase) / float64(s.period)
+
+		var v float64
+		switch p.Wave {
+		case Square:
+			if fp < s.squareDuty {
+				v = 0.5
+			} else {
+				v = -0.5
+			}
+		case Sawtooth:
+			v = 1 - fp*2
+		case Sine:
+			v = math.Sin(fp * 2 * math.Pi)
+		case Noise:
+			v = s.noiseBuf[s.phase*32/s.period]
+		}
+
+		// Low-pass filter.
+		pp := s.fltp
+		s.fltw *= s.fltwd
+		if s.fltw < 0 {
+			s.fltw = 0
+		}
+		if s.fltw > 0.1 {
+			s.fltw = 0.1
+		}
+		if p.LPFFreq != 1 {
+			s.fltdp += (v - s.fltp) * s.fltw
+			s.fltdp -= s.fltdp * s.fltdmp
+		} else {
+			s.fltp = v
+			s.fltdp = 0
+		}
+		s.fltp += s.fltdp
+
+		// High-pass filter.
+		s.fltphp += s.fltp - pp
+		s.fltphp -= s.fltphp * s.flthp
+		v = s.fltphp
+
+		// Phaser.
+		s.phaser[s.ipp&1023] = v
+		v += s.phaser[(s.ipp-s.iphase+1024)&1023]
+		s.ipp = (s.ipp + 1) & 1023
+
+		ssample += v * s.envVol
+	}
+
+	ssample = ssample / 8 * masterVolume
+	ssample *= 2 * p.Volume
+	if ssample > 1 {
+		ssample = 1
+	}
+	if ssample < -1 {
+		ssample = -1
+	}
+	return ssample
+}
+
+// Synthesize returns the sound of p as mono samples at SampleRate frames per
+// second.
+func (p *Params) Synthesize() []float32 {
+	s := &synth{p: p, noise: rand.New(rand.NewSource(1)), playing: true}
+	s.reset(false)
+
+	var out []float32
+	for s.playing {
+		v := s.sample()
+		if !s.playing {
+			break
+		}
+		out = append(out, float32(v))
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spatial places mixer voices in a 2D world around a listener, so
+// sounds come from the direction of what made them and fade with distance.
+//
+// Usage
+//
+// Attach each sound to the scene after playing it, move emitters and the
+// listener as the game runs, and call Update once per frame:
+//
+//  scene := spatial.NewScene(800)
+//  ...
+//  voice, err := mixer.PlayVoice(-1, explosion, 0)
+//  if err == nil {
+//      scene.Attach(voice, x, y)
+//  }
+//  ...
+//  scene.Listener.X, scene.Listener.Y = player.X, player.Y
+//  scene.Update()
+//
+// World coordinates are the ones of the screen of a top-down game: X grows to
+// the right and Y grows down.
+package spatial
+
+import (
+	"grate/backend/sdl2/mixer"
+	"math"
+)
+
+// Listener is the point sounds are heard from.
+type Listener struct {
+	X, Y float64
+
+	// Angle is the direction the listener faces in degrees, clockwise from
+	// up (negative Y).  Leave it at 0 for games where the screen does not
+	// turn with the player.
+	Angle float64
+}
+
+// Emitter is a sound playing at a point in the world.
+type Emitter struct {
+	X, Y  float64
+	voice mixer.Voice
+}
+
+// Voice returns the sound of e.
+func (e *Emitter) Voice() mixer.Voice {
+	return e.voice
+}
+
+// Channel returns the mixer channel e is playing on.
+func (e *Emitter) Channel() int {
+	return e.voice.Channel()
+}
+
+// Move moves e to x, y.
+func (e *Emitter) Move(x, y float64) {
+	e.X, e.Y = x, y
+}
+
+// Scene positions the voices attached to it relative to its Listener.  Voices
+// are used instead of channel numbers so that an emitter never moves a later
+// sound that reuses its channel.
+type Scene struct {
+	Listener Listener
+
+	// MaxDistance is the distance at which sounds are quietest.  Sounds
+	// further away are as quiet, but not silent.
+	MaxDistance float64
+
+	emitters map[mixer.Voice]*Emitter
+}
+
+// NewScene returns an empty scene where sounds fade out over maxDistance.
+func NewScene(maxDistance float64) *Scene {
+	return &Scene{MaxDistance: maxDistance, emitters: make(map[mixer.Voice]*Emitter)}
+}
+
+// Attach places voice at x, y and returns its emitter.  A voice that is
+// already attached is moved instead.  Attach positions the voice at once, so
+// the first samples already come from the right direction.
+func (s *Scene) Attach(voice mixer.Voice, x, y float64) *Emitter {
+	e, ok := s.emitters[voice]
+	if !ok {
+		e = &Emitter{voice: voice}
+		s.emitters[voice] = e
+	}
+	e.Move(x, y)
+	s.position(e)
+	return e
+}
+
+// Detach removes voice from s and moves its sound back to the listener.
+func (s *Scene) Detach(voice mixer.Voice) {
+	if _, ok := s.emitters[voice]; !ok {
+		return
+	}
+	delete(s.emitters, voice)
+	voice.SetPosition(0, 0)
+}
+
+// Emitter returns the emitter of voice, or nil if it is not attached.
+func (s *Scene) Emitter(voice mixer.Voice) *Emitter {
+	return s.emitters[voice]
+}
+
+// Update positions every attached voice that is still playing, and drops the
+// voices that ended.
+func (s *Scene) Update() {
+	for v, e := range s.emitters {
+		if !v.Playing() {
+			delete(s.emitters, v)
+			continue
+		}
+		s.position(e)
+	}
+}
+
+// position places e, unless its sound has ended and the channel has been
+// reused.
+func (s *Scene) position(e *Emitter) {
+	angle, distance := s.Locate(e.X, e.Y)
+	e.voice.SetPosition(angle, distance)
+}
+
+// Locate returns the angle and distance of the point x, y as
+// mixer.SetPosition takes them.
+func (s *Scene) Locate(x, y float64) (angle int16, distance uint8) {
+	dx, dy := x-s.Listener.X, y-s.Listener.Y
+
+	d := math.Hypot(dx, dy)
+	if s.MaxDistance > 0 {
+		d = d / s.MaxDistance * 255
+	}
+	if d > 255 {
+		d = 255
+	}
+	if d == 0 {
+		return 0, 0
+	}
+
+	// atan2(dx, -dy) is the angle clockwise from up.
+	a := math.Atan2(dx, -dy)*180/math.Pi - s.Listener.Angle
+	a = math.Mod(a, 360)
+	if a < 0 {
+		a += 360
+	}
+	return int16(a), uint8(d)
+}
@@ -0,0 +1,135 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "tap.h"
+
+// tap is a ring buffer with a single writer, the post-mix callback on the
+// audio thread, and a single reader.  head and tail count bytes written and
+// read, they wrap around and only their difference matters.  size is a
+// power of two.
+//
+// gen identifies the running tap, so a stopped Tap can not touch a later one.
+// lock guards buf and gen against stopTap freeing the ring while readTap
+// copies from it.  postMix does not take it, stopTap removes the hook before
+// freeing the ring.
+static struct {
+	SDL_SpinLock lock;
+	Uint32 gen;
+	Uint8 *buf;
+	Uint32 size;
+	SDL_atomic_t head;
+	SDL_atomic_t tail;
+	SDL_atomic_t overflows;
+} tap;
+
+static void postMix(void *udata, Uint8 *stream, int len) {
+	Uint32 head = (Uint32)SDL_AtomicGet(&tap.head);
+	Uint32 tail = (Uint32)SDL_AtomicGet(&tap.tail);
+	Uint32 off, first;
+
+	if ((Uint32)len > tap.size - (head - tail)) {
+		SDL_AtomicAdd(&tap.overflows, 1);
+		return;
+	}
+
+	off = head & (tap.size - 1);
+	first = tap.size - off;
+	if (first > (Uint32)len) {
+		first = len;
+	}
+	SDL_memcpy(tap.buf + off, stream, first);
+	SDL_memcpy(tap.buf, stream + first, len - first);
+	SDL_AtomicSet(&tap.head, (int)(head + len));
+}
+
+int startTap(int size, Uint32 *gen) {
+	Uint8 *buf;
+
+	SDL_AtomicLock(&tap.lock);
+	if (tap.buf != NULL) {
+		SDL_AtomicUnlock(&tap.lock);
+		return SDL_SetError("mixer: a tap is already running");
+	}
+	buf = SDL_malloc(size);
+	if (buf == NULL) {
+		SDL_AtomicUnlock(&tap.lock);
+		return SDL_OutOfMemory();
+	}
+	tap.buf = buf;
+	tap.size = size;
+	tap.gen++;
+	if (tap.gen == 0) {
+		tap.gen++;
+	}
+	*gen = tap.gen;
+	SDL_AtomicSet(&tap.head, 0);
+	SDL_AtomicSet(&tap.tail, 0);
+	SDL_AtomicSet(&tap.overflows, 0);
+	Mix_SetPostMix(postMix, NULL);
+	SDL_AtomicUnlock(&tap.lock);
+	return 0;
+}
+
+void stopTap(Uint32 gen) {
+	SDL_AtomicLock(&tap.lock);
+	if (tap.buf != NULL && tap.gen == gen) {
+		// Mix_SetPostMix locks the audio device, so postMix is not
+		// running once it returns.
+		Mix_SetPostMix(NULL, NULL);
+		SDL_free(tap.buf);
+		tap.buf = NULL;
+	}
+	SDL_AtomicUnlock(&tap.lock);
+}
+
+// readTap returns the number of bytes read, or -1 if the tap gen has been
+// stopped.
+int readTap(Uint32 gen, Uint8 *dst, int len) {
+	Uint32 head, tail, n, off, first;
+
+	SDL_AtomicLock(&tap.lock);
+	if (tap.buf == NULL || tap.gen != gen) {
+		SDL_AtomicUnlock(&tap.lock);
+		return -1;
+	}
+	head = (Uint32)SDL_AtomicGet(&tap.head);
+	tail = (Uint32)SDL_AtomicGet(&tap.tail);
+	n = head - tail;
+	if (n > (Uint32)len) {
+		n = len;
+	}
+	off = tail & (tap.size - 1);
+	first = tap.size - off;
+	if (first > n) {
+		first = n;
+	}
+	SDL_memcpy(dst, tap.buf + off, first);
+	SDL_memcpy(dst + first, tap.buf, n - first);
+	SDL_AtomicSet(&tap.tail, (int)(tail + n));
+	SDL_AtomicUnlock(&tap.lock);
+	return n;
+}
+
+int tapAvailable(Uint32 gen) {
+	int n = 0;
+
+	SDL_AtomicLock(&tap.lock);
+	if (tap.buf != NULL && tap.gen == gen) {
+		n = (int)((Uint32)SDL_AtomicGet(&tap.head) -
+			(Uint32)SDL_AtomicGet(&tap.tail));
+	}
+	SDL_AtomicUnlock(&tap.lock);
+	return n;
+}
+
+int tapOverflows(Uint32 gen) {
+	int n = 0;
+
+	SDL_AtomicLock(&tap.lock);
+	if (tap.gen == gen) {
+		n = SDL_AtomicGet(&tap.overflows);
+	}
+	SDL_AtomicUnlock(&tap.lock);
+	return n;
+}
@@ -0,0 +1,140 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "tap.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// Tap copies the final mix, everything the audio device plays, into a ring
+// buffer that the program reads at its own pace.  The audio thread never
+// waits for the reader: when the ring is full the mix is dropped and counted
+// in Overflows.
+//
+// Only one goroutine may read from a Tap.  The mixer has a single post-mix
+// hook, so only one Tap can run at a time.  A Tap that has been stopped stays
+// stopped: its methods do not touch a Tap started after it.
+type Tap struct {
+	gen   C.Uint32 // Identifies the tap in tap.c
+	spec  SampleBuffer
+	frame int
+}
+
+// errTapStopped is returned by Read once the Tap has been stopped.
+var errTapStopped = errors.New("mixer: tap is stopped")
+
+// StartTap starts copying the final mix into a ring of size bytes, rounded up
+// to a power of two.  The ring must hold all the audio played between two
+// reads, a second of audio is a safe size for reading once per frame.
+//
+// OpenAudio must be called before StartTap.
+func StartTap(size int) (*Tap, error) {
+	format, frequency, channels, opened := QuerySpec()
+	if opened == 0 {
+		return nil, errors.New("mixer: audio is not open")
+	}
+	if size <= 0 || size > 1<<30 {
+		return nil, errors.New("mixer: invalid tap size")
+	}
+
+	ring := 1
+	for ring < size {
+		ring <<= 1
+	}
+	var gen C.Uint32
+	if C.startTap(C.int(ring), &gen) != 0 {
+		return nil, sdlError(0)
+	}
+
+	t := &Tap{gen: gen, spec: SampleBuffer{Format: format, Channels: channels,
+		Frequency: frequency}}
+	t.frame = channels * sdl.AudioFormat(format).BitSize() / 8
+	return t, nil
+}
+
+// Stop stops t and frees its ring.  Audio that was not read is lost.  Stop
+// does nothing if t has already been stopped.
+func (t *Tap) Stop() {
+	C.stopTap(t.gen)
+}
+
+// Spec returns the format of the audio in t, for example to write it to a
+// WAV file with sdl.NewWAVWriter.
+func (t *Tap) Spec() *sdl.AudioSpec {
+	return &sdl.AudioSpec{
+		Freq:     int32(t.spec.Frequency),
+		Format:   sdl.AudioFormat(t.spec.Format),
+		Channels: uint8(t.spec.Channels),
+	}
+}
+
+// Available returns the number of bytes ready to read.
+func (t *Tap) Available() int {
+	return int(C.tapAvailable(t.gen))
+}
+
+// Overflows returns the number of mix buffers dropped because the ring was
+// full.
+func (t *Tap) Overflows() int {
+	return int(C.tapOverflows(t.gen))
+}
+
+// Read moves up to len(p) bytes of audio from the ring to p.  Only whole
+// sample frames are read.  Like bytes.Buffer, Read returns io.EOF when no
+// audio is ready, and more can be read once the mixer has played more.  Once
+// t has been stopped Read returns an error.
+func (t *Tap) Read(p []byte) (int, error) {
+	n := len(p)
+	if t.frame > 0 {
+		n -= n % t.frame
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	r := int(C.readTap(t.gen, (*C.Uint8)(unsafe.Pointer(&p[0])), C.int(n)))
+	if r == -1 {
+		return 0, errTapStopped
+	}
+	if r == 0 {
+		return 0, io.EOF
+	}
+	return r, nil
+}
+
+// Samples returns p, audio read from t, as a SampleBuffer for the meters.
+func (t *Tap) Samples(p []byte) SampleBuffer {
+	b := t.spec
+	b.Bytes = p
+	return b
+}
+
+// Drain writes all the audio that is ready to w, for example a WAV file
+// created with sdl.NewWAVWriter(file, t.Spec()).  It returns the number of
+// bytes written.
+func (t *Tap) Drain(w io.Writer) (int64, error) {
+	buf := make([]byte, 16*1024)
+	var total int64
+	for {
+		n, err := t.Read(buf)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		m, err := w.Write(buf[:n])
+		total += int64(m)
+		if err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL2/SDL_mixer.h"
+
+extern int startTap(int size, Uint32 *gen);
+extern void stopTap(Uint32 gen);
+extern int readTap(Uint32 gen, Uint8 *dst, int len);
+extern int tapAvailable(Uint32 gen);
+extern int tapOverflows(Uint32 gen);
@@ -0,0 +1,212 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixer
+
+// #include "SDL2/SDL_mixer.h"
+import "C"
+
+import (
+	"grate/backend/sdl2"
+	"sync"
+)
+
+// maxVolume is the volume of a channel that is not turned down.
+const maxVolume = C.MIX_MAX_VOLUME
+
+// voiceGens counts the sounds started on each channel, so a Voice can tell
+// whether its channel has been reused.  Sounds are only started through
+// startVoice, and Voice methods act on a channel with voiceGens held, so a
+// channel can not be reused between the check and the action.
+//
+// It also owns the volume of the channels: a channel plays at the volume set
+// with SetChannelVolume times the volume of its Voice, see applyVolume.
+//
+// voiceGens is never taken on the audio thread, so it can be held while
+// calling the mixer.
+var voiceGens = struct {
+	sync.Mutex
+	m      map[int]uint64
+	base   map[int]int // Volumes set with SetChannelVolume
+	volume map[int]int // Volumes set with Voice.SetVolume for the playing sound
+}{m: make(map[int]uint64), base: make(map[int]int), volume: make(map[int]int)}
+
+// startVoice calls start, which starts a sound and returns its channel or
+// -1, and returns a Voice for the sound.  A volume set on an earlier Voice of
+// the channel is dropped.
+func startVoice(start func() C.int) (Voice, error) {
+	voiceGens.Lock()
+	defer voiceGens.Unlock()
+
+	r := int(start())
+	if r == -1 {
+		return Voice{}, sdlError(r)
+	}
+	if _, ok := voiceGens.volume[r]; ok {
+		delete(voiceGens.volume, r)
+		applyVolume(r)
+	}
+	voiceGens.m[r]++
+	return Voice{r, voiceGens.m[r]}, nil
+}
+
+// applyVolume sets the volume of channel to its base volume times the volume
+// of its Voice.  voiceGens must be held.
+func applyVolume(channel int) {
+	base, ok := voiceGens.base[channel]
+	if !ok {
+		base = maxVolume
+	}
+	volume, ok := voiceGens.volume[channel]
+	if !ok {
+		volume = maxVolume
+	}
+	Volume(channel, base*volume/maxVolume)
+}
+
+// SetChannelVolume sets the base volume of channel, from 0 to 128.  Unlike
+// Volume it keeps the volume set with Voice.SetVolume for the sound playing on
+// the channel: the channel plays at the base volume times the volume of the
+// Voice.  Use it for volumes that belong to the channel, such as the volume of
+// a bus.  The base volume is kept until the next SetChannelVolume, Volume
+// overrides it until then.
+func SetChannelVolume(channel, volume int) {
+	voiceGens.Lock()
+	defer voiceGens.Unlock()
+
+	voiceGens.base[channel] = clampVolume(volume)
+	applyVolume(channel)
+}
+
+func clampVolume(volume int) int {
+	if volume < 0 {
+		return 0
+	}
+	if volume > maxVolume {
+		return maxVolume
+	}
+	return volume
+}
+
+// Voice is one sound playing on a channel.  Unlike a channel number, a Voice
+// keeps referring to its own sound after the channel is reused for another
+// one: once the sound has ended, all methods of the Voice do nothing.
+//
+// The zero Voice is never playing.
+type Voice struct {
+	channel int
+	gen     uint64
+}
+
+// PlayVoice is the same as PlayChannel, but returns a Voice.
+func PlayVoice(channel int, chunk Chunk, loops int) (Voice, error) {
+	return PlayVoiceTimed(channel, chunk, loops, -1)
+}
+
+// PlayVoiceTimed is the same as PlayChannelTimed, but returns a Voice.
+func PlayVoiceTimed(channel int, chunk Chunk, loops, ticks int) (Voice, error) {
+	return startVoice(func() C.int {
+		return C.Mix_PlayChannelTimed(C.int(channel), chunk.ptr,
+			C.int(loops), C.int(ticks))
+	})
+}
+
+// FadeInVoice is the same as FadeInChannel, but returns a Voice.
+func FadeInVoice(channel int, chunk Chunk, loops, ms int) (Voice, error) {
+	return FadeInVoiceTimed(channel, chunk, loops, ms, -1)
+}
+
+// FadeInVoiceTimed is the same as FadeInChannelTimed, but returns a Voice.
+func FadeInVoiceTimed(channel int, chunk Chunk, loops, ms, ticks int) (Voice, error) {
+	return startVoice(func() C.int {
+		return C.Mix_FadeInChannelTimed(C.int(channel), chunk.ptr,
+			C.int(loops), C.int(ms), C.int(ticks))
+	})
+}
+
+// Channel returns the channel v was started on.
+func (v Voice) Channel() int {
+	return v.channel
+}
+
+// act calls f with the channel of v, with voiceGens held, if v has not
+// ended.  It returns true if f was called.
+func (v Voice) act(f func(channel int)) bool {
+	if v.gen == 0 {
+		return false
+	}
+	voiceGens.Lock()
+	defer voiceGens.Unlock()
+
+	if voiceGens.m[v.channel] != v.gen || Playing(v.channel) == 0 {
+		return false
+	}
+	if f != nil {
+		f(v.channel)
+	}
+	return true
+}
+
+// Playing returns true if v has not ended yet.  A paused voice is playing.
+func (v Voice) Playing() bool {
+	return v.act(nil)
+}
+
+// Stop halts v.
+func (v Voice) Stop() {
+	v.act(func(channel int) { HaltChannel(channel) })
+}
+
+// FadeOut fades v out over ms milliseconds and then halts it.
+func (v Voice) FadeOut(ms int) {
+	v.act(func(channel int) { FadeOutChannel(channel, ms) })
+}
+
+// Expire halts v after ticks milliseconds.
+func (v Voice) Expire(ticks int) {
+	v.act(func(channel int) { ExpireChannel(channel, ticks) })
+}
+
+// SetVolume sets the volume of v, from 0 to 128.  It scales the base volume
+// of the channel set with SetChannelVolume, and is dropped when the next sound
+// is started on the channel.
+func (v Voice) SetVolume(volume int) {
+	v.act(func(channel int) {
+		voiceGens.volume[channel] = clampVolume(volume)
+		applyVolume(channel)
+	})
+}
+
+// SetPosition places v like SetPosition.  It does nothing if v has ended.
+func (v Voice) SetPosition(angle int16, distance uint8) error {
+	var err error
+	v.act(func(channel int) { err = SetPosition(channel, angle, distance) })
+	return err
+}
+
+// Pause pauses v.
+func (v Voice) Pause() {
+	v.act(func(channel int) { Pause(channel) })
+}
+
+// Resume resumes v after Pause.
+func (v Voice) Resume() {
+	v.act(func(channel int) { Resume(channel) })
+}
+
+// Paused returns true if v is playing and paused.
+func (v Voice) Paused() bool {
+	paused := false
+	v.act(func(channel int) { paused = Paused(channel) != 0 })
+	return paused
+}
+
+// Finished returns true if event is a HookChannelFinished event for the
+// channel of v and v has ended.  The event of a sound that ended is not
+// mistaken for the end of a later sound on the same channel, as long as the
+// later sound is still playing.
+func (v Voice) Finished(event sdl.Event) bool {
+	channel, _, ok := ChannelFinished(event)
+	return ok && channel == v.channel && !v.Playing()
+}
@@ -0,0 +1,305 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package replay records the events a program reads from the SDL event queue
+// and plays them back later on the same frames.
+//
+// Usage
+//
+// Read events through a Recorder and call NextFrame once per frame:
+//
+//  rec, err := replay.NewRecorder(file)
+//  ...
+//  for running {
+//      for rec.PollEvent(&event) != 0 {
+//          ...
+//      }
+//      rec.NextFrame()
+//  }
+//  rec.Flush()
+//
+// To replay, call Inject at the start of every frame, before polling:
+//
+//  p, err := replay.NewPlayer(file)
+//  ...
+//  for frame := uint64(0); running; frame++ {
+//      p.Inject(frame)
+//      for sdl.PollEvent(&event) != 0 {
+//          ...
+//      }
+//  }
+//
+// Together with the dummy video driver (set the SDL_VIDEODRIVER environment
+// variable to "dummy") a recording can drive a regression test without a
+// display.
+//
+// User events (USEREVENT and up) and SYSWMEVENT are not recorded.  User
+// events are pushed by the program itself, so it pushes them again during a
+// replay, and system events only make sense to the window they were sent to.
+// Recordings store events in the machine's native layout and can only be
+// played back on machines with the same byte order and pointer size.
+package replay
+
+import (
+	"grate/backend/sdl2"
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// Version is the version of the recording format written by Recorder.
+const Version = 1
+
+var magic = [8]byte{'S', 'D', 'L', 'R', 'P', 'L', 'A', 'Y'}
+
+const eventSize = int(unsafe.Sizeof(sdl.EventUnion{}))
+
+// header starts every recording.
+type header struct {
+	Magic     [8]byte
+	Version   uint32
+	EventSize uint32 // The size of sdl.EventUnion on the recording machine
+}
+
+// recordHeader precedes every event in a recording.  It is followed by
+// the event and TextLen bytes of text.
+type recordHeader struct {
+	Frame   uint64
+	TextLen uint32 // Length of the file name or text of drop events
+}
+
+// record is a single recorded event.
+type record struct {
+	frame uint64
+	event sdl.EventUnion
+	text  string
+}
+
+// ErrFormat is returned when reading a recording that is not valid.
+var ErrFormat = errors.New("replay: invalid recording")
+
+// maxTextLen is the longest text NewPlayer accepts for an event, so a corrupt
+// recording can not make it allocate gigabytes.
+const maxTextLen = 1 << 16
+
+func eventBytes(event *sdl.EventUnion) []byte {
+	return (*[1 << 16]byte)(unsafe.Pointer(event))[:eventSize:eventSize]
+}
+
+// recorded returns true if events of type t are recorded.
+func recorded(t sdl.EventType) bool {
+	return t != sdl.SYSWMEVENT && t < sdl.USEREVENT
+}
+
+// Recorder writes the events it is given to a recording, together with the
+// frame they were read on.
+type Recorder struct {
+	w     *bufio.Writer
+	frame uint64
+	err   error
+}
+
+// NewRecorder starts a recording on w.  Call Flush when the recording is
+// done.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	r := &Recorder{w: bufio.NewWriter(w)}
+
+	h := header{
+		Magic:     magic,
+		Version:   Version,
+		EventSize: uint32(eventSize),
+	}
+	if err := binary.Write(r.w, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Frame returns the current frame number.  The first frame is 0.
+func (r *Recorder) Frame() uint64 {
+	return r.frame
+}
+
+// NextFrame advances the recording to the next frame.
+func (r *Recorder) NextFrame() {
+	r.frame++
+}
+
+// Record adds event to the recording on the current frame.  Events that are
+// not recorded are ignored.  Once writing fails Record keeps returning the
+// same error.
+func (r *Recorder) Record(event *sdl.EventUnion) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !recorded(event.Type) {
+		return nil
+	}
+
+	rec := record{frame: r.frame, event: *event}
+	if d, ok := rec.event.Convert().(*sdl.DropEvent); ok {
+		// The file name is written out separately, never write the
+		// pointer to it.
+		rec.text = d.File()
+		zero := sdl.DropEvent{Type: d.Type, Timestamp: d.Timestamp, WindowID: d.WindowID}
+		sdl.CopyEventToEventUnion(&zero, &rec.event)
+	}
+
+	r.err = writeRecord(r.w, &rec)
+	return r.err
+}
+
+func writeRecord(w io.Writer, rec *record) error {
+	rh := recordHeader{
+		Frame:   rec.frame,
+		TextLen: uint32(len(rec.text)),
+	}
+	if err := binary.Write(w, binary.LittleEndian, &rh); err != nil {
+		return err
+	}
+	if _, err := w.Write(eventBytes(&rec.event)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, rec.text)
+	return err
+}
+
+// PollEvent is the same as sdl.PollEvent, but records the event it returns.
+// User events are not recorded, so those pushed with sdl.PushUserEvent must
+// still be released with sdl.UserEvent.Release.
+func (r *Recorder) PollEvent(event *sdl.EventUnion) int {
+	if event == nil {
+		return sdl.PollEvent(nil)
+	}
+
+	n := sdl.PollEvent(event)
+	if n != 0 {
+		r.Record(event)
+	}
+	return n
+}
+
+// PeepEvents is the same as sdl.PeepEvents, but records the events it
+// removes from the event queue when action is GETEVENT.
+func (r *Recorder) PeepEvents(events []sdl.EventUnion, action sdl.EventAction, minType, maxType sdl.EventType) (int, error) {
+	n, err := sdl.PeepEvents(events, action, minType, maxType)
+	if action == sdl.GETEVENT {
+		for i := 0; i < n; i++ {
+			r.Record(&events[i])
+		}
+	}
+	return n, err
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (r *Recorder) Flush() error {
+	if r.err != nil {
+		return r.err
+	}
+	r.err = r.w.Flush()
+	return r.err
+}
+
+// Player pushes the events of a recording back onto the event queue.
+type Player struct {
+	records []record
+	next    int
+}
+
+// NewPlayer reads a recording from rd.
+func NewPlayer(rd io.Reader) (*Player, error) {
+	br := bufio.NewReader(rd)
+
+	var h header
+	if err := binary.Read(br, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	if h.Magic != magic {
+		return nil, ErrFormat
+	}
+	if h.Version != Version {
+		return nil, fmt.Errorf("replay: unsupported version %d", h.Version)
+	}
+	if int(h.EventSize) != eventSize {
+		return nil, fmt.Errorf("replay: recorded event size %d does not match %d", h.EventSize, eventSize)
+	}
+
+	p := &Player{}
+	for {
+		var rh recordHeader
+		err := binary.Read(br, binary.LittleEndian, &rh)
+		if err == io.EOF {
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := record{frame: rh.Frame}
+		if _, err := io.ReadFull(br, eventBytes(&rec.event)); err != nil {
+			return nil, ErrFormat
+		}
+		if rh.TextLen > maxTextLen {
+			return nil, ErrFormat
+		}
+		text := make([]byte, rh.TextLen)
+		if _, err := io.ReadFull(br, text); err != nil {
+			return nil, ErrFormat
+		}
+		rec.text = string(text)
+
+		p.records = append(p.records, rec)
+	}
+}
+
+// Len returns the number of events in the recording.
+func (p *Player) Len() int {
+	return len(p.records)
+}
+
+// Done returns true when every event has been injected.
+func (p *Player) Done() bool {
+	return p.next >= len(p.records)
+}
+
+// Inject pushes the events recorded on frame, and any earlier frames that
+// have not been injected yet, onto the event queue.  It returns the number
+// of events pushed, events dropped by an event filter are not counted.
+//
+// SDL stamps pushed events with the time they are pushed, so the injected
+// events carry the time of the replay and not the recorded Timestamp.
+//
+// The pushed DropEvents carry a newly allocated file name, FreeFile must be
+// called for them as usual.  Go event filters only see the events if Inject
+// is called on the event thread, see sdl.EventFilter.
+func (p *Player) Inject(frame uint64) (int, error) {
+	n := 0
+	for ; p.next < len(p.records); p.next++ {
+		rec := &p.records[p.next]
+		if rec.frame > frame {
+			break
+		}
+
+		event := rec.event
+		if d, ok := event.Convert().(*sdl.DropEvent); ok && rec.text != "" {
+			d.SetFile(rec.text)
+		}
+		r, err := sdl.PushEvent(&event)
+		if err != nil {
+			return n, err
+		}
+		if r == 1 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Rewind restarts the playback from the first event.
+func (p *Player) Rewind() {
+	p.next = 0
+}
@@ -0,0 +1,96 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package replay
+
+import (
+	"grate/backend/sdl2"
+	"bytes"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var evu sdl.EventUnion
+	key := sdl.KeyboardEvent{Type: sdl.KEYDOWN, Timestamp: 10, State: sdl.PRESSED}
+	key.Keysym.Scancode = sdl.SCANCODE_SPACE
+	sdl.CopyEventToEventUnion(&key, &evu)
+	if err := rec.Record(&evu); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.NextFrame()
+	rec.NextFrame()
+
+	user := sdl.UserEvent{Type: sdl.USEREVENT}
+	sdl.CopyEventToEventUnion(&user, &evu)
+	rec.Record(&evu)
+
+	quit := sdl.QuitEvent{Type: sdl.QUIT, Timestamp: 20}
+	sdl.CopyEventToEventUnion(&quit, &evu)
+	rec.Record(&evu)
+
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPlayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != 2 {
+		t.Fatalf("got %d events, want 2", p.Len())
+	}
+
+	if p.records[0].frame != 0 {
+		t.Errorf("got frame %d for first event, want 0", p.records[0].frame)
+	}
+	k, ok := p.records[0].event.Convert().(*sdl.KeyboardEvent)
+	if !ok || k.Keysym.Scancode != sdl.SCANCODE_SPACE || k.Timestamp != 10 {
+		t.Errorf("first event was not restored: %#v", p.records[0].event.Convert())
+	}
+
+	if p.records[1].frame != 2 {
+		t.Errorf("got frame %d for second event, want 2", p.records[1].frame)
+	}
+	if p.records[1].event.Type != sdl.QUIT {
+		t.Errorf("got %s for second event, want QUIT", p.records[1].event.Type)
+	}
+}
+
+func TestBadMagic(t *testing.T) {
+	_, err := NewPlayer(bytes.NewReader(make([]byte, 16)))
+	if err != ErrFormat {
+		t.Errorf("got %v, want ErrFormat", err)
+	}
+}
+
+func TestTextTooLong(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := record{text: "x"}
+	if err := writeRecord(&buf, &r); err != nil {
+		t.Fatal(err)
+	}
+	// Patch TextLen, which follows the frame number, to claim 4 GiB of text.
+	b := buf.Bytes()
+	n := len(b) - eventSize - len(r.text) - 4
+	b[n], b[n+1], b[n+2], b[n+3] = 0xff, 0xff, 0xff, 0xff
+
+	if _, err := NewPlayer(bytes.NewReader(b)); err != ErrFormat {
+		t.Errorf("got %v, want ErrFormat", err)
+	}
+}
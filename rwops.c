@@ -0,0 +1,57 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "rwops.h"
+#include "_cgo_export.h"
+
+#define goHandle(ctx) ((uintptr_t)(ctx)->hidden.unknown.data1)
+
+static Sint64 goSize(SDL_RWops *ctx) {
+	return goRWopsSize(goHandle(ctx));
+}
+
+static Sint64 goSeek(SDL_RWops *ctx, Sint64 offset, int whence) {
+	return goRWopsSeek(goHandle(ctx), offset, whence);
+}
+
+static size_t goRead(SDL_RWops *ctx, void *ptr, size_t size, size_t maxnum) {
+	return goRWopsRead(goHandle(ctx), ptr, size, maxnum);
+}
+
+static size_t goWrite(SDL_RWops *ctx, const void *ptr, size_t size, size_t num) {
+	return goRWopsWrite(goHandle(ctx), (void *)ptr, size, num);
+}
+
+static int goClose(SDL_RWops *ctx) {
+	int r = 0;
+
+	if (ctx != NULL) {
+		r = goRWopsClose(goHandle(ctx));
+		SDL_FreeRW(ctx);
+	}
+	return r;
+}
+
+SDL_RWops *newGoRWops(uintptr_t handle) {
+	SDL_RWops *ctx = SDL_AllocRW();
+
+	if (ctx != NULL) {
+		ctx->size = goSize;
+		ctx->seek = goSeek;
+		ctx->read = goRead;
+		ctx->write = goWrite;
+		ctx->close = goClose;
+		ctx->type = SDL_RWOPS_UNKNOWN;
+		ctx->hidden.unknown.data1 = (void *)handle;
+	}
+	return ctx;
+}
+
+// The SDL_RW* functions are macros in older versions of SDL, cgo can only
+// call them through these wrappers.
+Sint64 rwSize(SDL_RWops *ctx) { return SDL_RWsize(ctx); }
+Sint64 rwSeek(SDL_RWops *ctx, Sint64 offset, int whence) { return SDL_RWseek(ctx, offset, whence); }
+size_t rwRead(SDL_RWops *ctx, void *ptr, size_t size, size_t maxnum) { return SDL_RWread(ctx, ptr, size, maxnum); }
+size_t rwWrite(SDL_RWops *ctx, const void *ptr, size_t size, size_t num) { return SDL_RWwrite(ctx, ptr, size, num); }
+int rwClose(SDL_RWops *ctx) { return SDL_RWclose(ctx); }
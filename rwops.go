@@ -0,0 +1,297 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+#include "rwops.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// RWops is a stream that SDL and its companion libraries read from and write
+// to.  Every loader that takes a RWops can load from a file, from memory, or
+// from any Go io.Reader.
+//
+// RWops implements io.Reader, io.Writer, io.Seeker, and io.Closer.
+type RWops C.SDL_RWops
+
+const (
+	RW_SEEK_SET = C.RW_SEEK_SET // Seek from the beginning of the data
+	RW_SEEK_CUR = C.RW_SEEK_CUR // Seek relative to the current read point
+	RW_SEEK_END = C.RW_SEEK_END // Seek relative to the end of the data
+)
+
+// RWFromFile creates a RWops for the named file.  mode is the same as the
+// mode argument of C's fopen, for example "rb" or "wb".
+func RWFromFile(file, mode string) (*RWops, error) {
+	cfile := C.CString(file)
+	defer C.free(unsafe.Pointer(cfile))
+	cmode := C.CString(mode)
+	defer C.free(unsafe.Pointer(cmode))
+
+	r := C.SDL_RWFromFile(cfile, cmode)
+	if r == nil {
+		return nil, sdlError(0)
+	}
+	return (*RWops)(unsafe.Pointer(r)), nil
+}
+
+// RWFromMem creates a RWops that reads from and writes to mem.  Writes can
+// not grow mem, writing past its end is an error.  mem is not copied and is
+// kept alive until the RWops is closed.
+func RWFromMem(mem []byte) (*RWops, error) {
+	return RWFromReader(&memRWops{mem: mem})
+}
+
+// RWFromReader creates a RWops that reads from r.  If r also implements
+// io.Seeker, io.Writer, or io.Closer the RWops will seek, write, and close r
+// too.  Without io.Seeker the RWops can only report its current position, so
+// loaders that need to seek will fail.
+//
+// r is called from whichever thread uses the RWops, which is the audio thread
+// for music that is streamed from r.  r is kept alive until the RWops is
+// closed, so close it, or have a loader free it, when you are done.
+func RWFromReader(r io.Reader) (*RWops, error) {
+	h := newHandle(&goRWops{r: r})
+	rw := C.newGoRWops(C.uintptr_t(h))
+	if rw == nil {
+		deleteHandle(h)
+		return nil, sdlError(0)
+	}
+	return (*RWops)(unsafe.Pointer(rw)), nil
+}
+
+func (rw *RWops) cptr() *C.SDL_RWops {
+	return (*C.SDL_RWops)(unsafe.Pointer(rw))
+}
+
+// Size returns the size of the data stream in rw.
+func (rw *RWops) Size() (int64, error) {
+	r := int64(C.rwSize(rw.cptr()))
+	if r < 0 {
+		return r, sdlError(int(r))
+	}
+	return r, nil
+}
+
+// Seek sets the offset for the next Read or Write on rw to offset,
+// interpreted according to whence: RW_SEEK_SET, RW_SEEK_CUR or RW_SEEK_END.
+// The io.Seek* constants have the same values.  Seek returns the new offset.
+func (rw *RWops) Seek(offset int64, whence int) (int64, error) {
+	r := int64(C.rwSeek(rw.cptr(), C.Sint64(offset), C.int(whence)))
+	if r < 0 {
+		return r, sdlError(int(r))
+	}
+	return r, nil
+}
+
+// Read reads up to len(p) bytes from rw into p.  It returns io.EOF when no
+// more data can be read.
+func (rw *RWops) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	n := int(C.rwRead(rw.cptr(), unsafe.Pointer(&p[0]), 1, C.size_t(len(p))))
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Write writes len(p) bytes from p to rw.
+func (rw *RWops) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	n := int(C.rwWrite(rw.cptr(), unsafe.Pointer(&p[0]), 1, C.size_t(len(p))))
+	if n < len(p) {
+		return n, sdlError(n)
+	}
+	return n, nil
+}
+
+// Close closes and frees rw.  rw must not be used after it has been closed.
+func (rw *RWops) Close() error {
+	r := C.rwClose(rw.cptr())
+	if r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// goRWops is the Go side of a RWops created by RWFromReader.
+type goRWops struct {
+	r   io.Reader
+	pos int64 // Current offset, used when r is not an io.Seeker
+}
+
+var errNoSeek = errors.New("RWops: reader does not implement io.Seeker")
+
+func (g *goRWops) seek(offset int64, whence int) (int64, error) {
+	if s, ok := g.r.(io.Seeker); ok {
+		return s.Seek(offset, whence)
+	}
+	if whence == RW_SEEK_CUR && offset == 0 {
+		return g.pos, nil
+	}
+	return -1, errNoSeek
+}
+
+func lookupRWops(h C.uintptr_t) *goRWops {
+	g, _ := handleValue(uintptr(h)).(*goRWops)
+	return g
+}
+
+func byteSlice(ptr unsafe.Pointer, n int) []byte {
+	b := []byte{}
+	sh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	sh.Data = uintptr(ptr)
+	sh.Len = n
+	sh.Cap = n
+	return b
+}
+
+//export goRWopsSize
+func goRWopsSize(h C.uintptr_t) C.Sint64 {
+	g := lookupRWops(h)
+	if g == nil {
+		return -1
+	}
+
+	cur, err := g.seek(0, RW_SEEK_CUR)
+	if err != nil {
+		SetError(err.Error())
+		return -1
+	}
+	end, err := g.seek(0, RW_SEEK_END)
+	if err != nil {
+		SetError(err.Error())
+		return -1
+	}
+	if _, err := g.seek(cur, RW_SEEK_SET); err != nil {
+		SetError(err.Error())
+		return -1
+	}
+	return C.Sint64(end)
+}
+
+//export goRWopsSeek
+func goRWopsSeek(h C.uintptr_t, offset C.Sint64, whence C.int) C.Sint64 {
+	g := lookupRWops(h)
+	if g == nil {
+		return -1
+	}
+
+	pos, err := g.seek(int64(offset), int(whence))
+	if err != nil {
+		SetError(err.Error())
+		return -1
+	}
+	g.pos = pos
+	return C.Sint64(pos)
+}
+
+//export goRWopsRead
+func goRWopsRead(h C.uintptr_t, ptr unsafe.Pointer, size, maxnum C.size_t) C.size_t {
+	g := lookupRWops(h)
+	if g == nil || size == 0 || maxnum == 0 {
+		return 0
+	}
+
+	n, err := io.ReadFull(g.r, byteSlice(ptr, int(size*maxnum)))
+	g.pos += int64(n)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		SetError(err.Error())
+	}
+	return C.size_t(n) / size
+}
+
+//export goRWopsWrite
+func goRWopsWrite(h C.uintptr_t, ptr unsafe.Pointer, size, num C.size_t) C.size_t {
+	g := lookupRWops(h)
+	if g == nil || size == 0 || num == 0 {
+		return 0
+	}
+
+	w, ok := g.r.(io.Writer)
+	if !ok {
+		SetError("RWops: reader does not implement io.Writer")
+		return 0
+	}
+
+	n, err := w.Write(byteSlice(ptr, int(size*num)))
+	g.pos += int64(n)
+	if err != nil {
+		SetError(err.Error())
+	}
+	return C.size_t(n) / size
+}
+
+//export goRWopsClose
+func goRWopsClose(h C.uintptr_t) C.int {
+	g := lookupRWops(h)
+	deleteHandle(uintptr(h))
+	if g == nil {
+		return 0
+	}
+
+	if c, ok := g.r.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			SetError(err.Error())
+			return -1
+		}
+	}
+	return 0
+}
+
+// memRWops reads from and writes to a fixed size byte slice.
+type memRWops struct {
+	mem []byte
+	pos int64
+}
+
+func (m *memRWops) Read(p []byte) (int, error) {
+	if m.pos >= int64(len(m.mem)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.mem[m.pos:])
+	m.pos += int64(n)
+	return n, nil
+}
+
+func (m *memRWops) Write(p []byte) (int, error) {
+	if m.pos >= int64(len(m.mem)) {
+		return 0, io.ErrShortWrite
+	}
+	n := copy(m.mem[m.pos:], p)
+	m.pos += int64(n)
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+func (m *memRWops) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case RW_SEEK_CUR:
+		offset += m.pos
+	case RW_SEEK_END:
+		offset += int64(len(m.mem))
+	}
+	if offset < 0 || offset > int64(len(m.mem)) {
+		return -1, errors.New("RWops: seek out of range")
+	}
+	m.pos = offset
+	return offset, nil
+}
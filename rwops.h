@@ -0,0 +1,12 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL.h"
+
+extern SDL_RWops *newGoRWops(uintptr_t handle);
+extern Sint64 rwSize(SDL_RWops *ctx);
+extern Sint64 rwSeek(SDL_RWops *ctx, Sint64 offset, int whence);
+extern size_t rwRead(SDL_RWops *ctx, void *ptr, size_t size, size_t maxnum);
+extern size_t rwWrite(SDL_RWops *ctx, const void *ptr, size_t size, size_t num);
+extern int rwClose(SDL_RWops *ctx);
@@ -46,6 +46,7 @@ func Init(flags InitFlags) error {
 	if r := int(C.SDL_Init(C.Uint32(flags))); r != 0 {
 		return sdlError(r)
 	}
+	setEventThread()
 	return nil
 }
 
@@ -54,6 +55,7 @@ func InitSubSystem(flags InitFlags) error {
 	if r := int(C.SDL_InitSubSystem(C.Uint32(flags))); r != 0 {
 		return sdlError(r)
 	}
+	setEventThread()
 	return nil
 }
 
@@ -13,6 +13,7 @@ package sdl
 #include "SDL.h"
 #include "SDL_syswm.h"
 #include "SDL_haptic.h"
+#include "compat.h"
 */
 import "C"
 
@@ -39,6 +40,12 @@ type DropEvent C.SDL_DropEvent
 type QuitEvent C.SDL_QuitEvent
 type UserEvent C.SDL_UserEvent
 type SysWMEvent C.SDL_SysWMEvent
+type CommonEvent C.SDL_CommonEvent
+type ClipboardEvent C.SDL_CommonEvent
+type RenderEvent C.SDL_CommonEvent
+type DisplayEvent C.SDL_DisplayEvent
+type AudioDeviceEvent C.SDL_AudioDeviceEvent
+type SensorEvent C.SDL_SensorEvent
 type EventUnion C.SDL_Event
 
 //SDL_joystick.h
@@ -221,14 +221,17 @@ type DollarGestureEvent struct {
 	Y          float32
 }
 
-// An event used to request a file open by the system.  This event is disabled
-// by default, you can enable it with EventState.
+// An event used to request a file open by the system.  These events are
+// disabled by default, you can enable them with EventState.
 //
-// If you enable this event, you must call FreeFile for all DropEvents.
+// DROPFILE and DROPTEXT carry a file name or text, DROPBEGIN and DROPCOMPLETE
+// mark the start and end of a drop of several items.  If you enable these
+// events, you must call FreeFile for all DropEvents.
 type DropEvent struct {
-	Type      EventType // DROPFILE
+	Type      EventType // DROPFILE, DROPTEXT, DROPBEGIN or DROPCOMPLETE
 	Timestamp uint32
 	file      *C.char
+	WindowID  uint32 // The window that was dropped on, if any
 }
 
 // The "quit requested" event
@@ -255,6 +258,57 @@ type SysWMEvent struct {
 	Msg       *SysWMmsg //driver dependent data
 }
 
+// Fields shared by every event.  Events that carry no other data, such as
+// APP_LOWMEMORY and KEYMAPCHANGED, are converted to a CommonEvent.
+type CommonEvent struct {
+	Type      EventType
+	Timestamp uint32
+}
+
+// The clipboard changed
+type ClipboardEvent struct {
+	Type      EventType // CLIPBOARDUPDATE
+	Timestamp uint32
+}
+
+// The render targets or the render device were reset and their contents need
+// to be updated
+type RenderEvent struct {
+	Type      EventType // RENDER_TARGETS_RESET or RENDER_DEVICE_RESET
+	Timestamp uint32
+}
+
+// Display state change event data
+type DisplayEvent struct {
+	Type      EventType // DISPLAYEVENT
+	Timestamp uint32
+	Display   uint32 // The associated display index
+	Event     DisplayEventID
+	_         uint8
+	_         uint8
+	_         uint8
+	Data1     int32 // Event dependent data
+}
+
+// Audio device event structure
+type AudioDeviceEvent struct {
+	Type      EventType // AUDIODEVICEADDED or AUDIODEVICEREMOVED
+	Timestamp uint32
+	Which     uint32 // The audio device index for ADD, AudioDeviceID for REMOVE
+	Iscapture uint8  // Zero if an output device, non-zero if a capture device
+	_         uint8
+	_         uint8
+	_         uint8
+}
+
+// Sensor event structure
+type SensorEvent struct {
+	Type      EventType // SENSORUPDATE
+	Timestamp uint32
+	Which     int32      // The instance ID of the sensor
+	Data      [6]float32 // Up to 6 values from the sensor
+}
+
 // EventUnion is used to hold a SDL_Event and is used for transferring events
 // between go and C.  To gain access to the other fields of an event you
 // will have to call the Convert method.
@@ -136,6 +136,12 @@ var structTests = []iPair{
 	{QuitEvent{}, testQuitEvent{}},
 	{UserEvent{}, testUserEvent{}},
 	{SysWMEvent{}, testSysWMEvent{}},
+	{CommonEvent{}, testCommonEvent{}},
+	{ClipboardEvent{}, testClipboardEvent{}},
+	{RenderEvent{}, testRenderEvent{}},
+	{DisplayEvent{}, testDisplayEvent{}},
+	{AudioDeviceEvent{}, testAudioDeviceEvent{}},
+	{SensorEvent{}, testSensorEvent{}},
 	{JoystickGUID{}, testJoystickGUID{}},
 	{Keysym{}, testKeysym{}},
 	{Color{}, testColor{}},
@@ -118,14 +118,54 @@ func (surf *Surface) Unlock() {
 	C.SDL_UnlockSurface((*C.SDL_Surface)(unsafe.Pointer(surf)))
 }
 
-/*
-Skipping
-SDL_LoadBMP_RW()
-SDL_LoadBMP()
-SDL_SaveBMP_RW()
-SDL_SaveBMP()
-Undecided on exposing RWops
-*/
+// LoadBMP_RW loads a surface from a BMP in src.  If freesrc is true src is
+// closed after it has been read, even if there is an error.
+func LoadBMP_RW(src *RWops, freesrc bool) (*Surface, error) {
+	var f C.int
+	if freesrc {
+		f = 1
+	}
+
+	r := C.SDL_LoadBMP_RW((*C.SDL_RWops)(unsafe.Pointer(src)), f)
+	if r == nil {
+		return nil, sdlError(0)
+	}
+	return (*Surface)(unsafe.Pointer(r)), nil
+}
+
+// LoadBMP loads a surface from a BMP file.
+func LoadBMP(file string) (*Surface, error) {
+	src, err := RWFromFile(file, "rb")
+	if err != nil {
+		return nil, err
+	}
+	return LoadBMP_RW(src, true)
+}
+
+// SaveBMP_RW saves surf as a BMP to dst.  If freedst is true dst is closed
+// after it has been written, even if there is an error.
+func (surf *Surface) SaveBMP_RW(dst *RWops, freedst bool) error {
+	var f C.int
+	if freedst {
+		f = 1
+	}
+
+	r := C.SDL_SaveBMP_RW((*C.SDL_Surface)(unsafe.Pointer(surf)),
+		(*C.SDL_RWops)(unsafe.Pointer(dst)), f)
+	if r != 0 {
+		return sdlError(int(r))
+	}
+	return nil
+}
+
+// SaveBMP saves surf as a BMP file.
+func (surf *Surface) SaveBMP(file string) error {
+	dst, err := RWFromFile(file, "wb")
+	if err != nil {
+		return err
+	}
+	return surf.SaveBMP_RW(dst, true)
+}
 
 // SetRLE enables RLE accleration for surf if flag is true, disables RLE
 // accleration if flag is false.
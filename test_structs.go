@@ -11,6 +11,7 @@ package sdl
 #include "SDL.h"
 #include "SDL_syswm.h"
 #include "SDL_haptic.h"
+#include "compat.h"
 */
 import "C"
 
@@ -37,6 +38,12 @@ type testDropEvent C.SDL_DropEvent
 type testQuitEvent C.SDL_QuitEvent
 type testUserEvent C.SDL_UserEvent
 type testSysWMEvent C.SDL_SysWMEvent
+type testCommonEvent C.SDL_CommonEvent
+type testClipboardEvent C.SDL_CommonEvent
+type testRenderEvent C.SDL_CommonEvent
+type testDisplayEvent C.SDL_DisplayEvent
+type testAudioDeviceEvent C.SDL_AudioDeviceEvent
+type testSensorEvent C.SDL_SensorEvent
 type testEventUnion C.SDL_Event
 
 //SDL_joystick.h
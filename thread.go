@@ -0,0 +1,96 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+*/
+import "C"
+
+import "runtime"
+
+// Package initialization runs on the main OS thread, keep the main goroutine
+// there so Main can service calls from the thread the program started on.
+func init() {
+	runtime.LockOSThread()
+}
+
+var (
+	// callQueue holds the calls submitted with Do that are waiting to be run
+	// by Main.
+	callQueue = make(chan func())
+
+	// mainThread is the id of the thread running Main.  It is set before
+	// Main starts any goroutines and never changes afterwards.
+	mainThread C.SDL_threadID
+)
+
+// Main runs main in a new goroutine and services calls made with Do on the
+// current OS thread until main returns.  Main must be called from the
+// program's main function, which is locked to the main OS thread during
+// package initialization, so that calls made with Do run on the same thread
+// that calls Init and CreateWindow.
+//
+//  func main() {
+//      sdl.Main(run)
+//  }
+//
+//  func run() {
+//      sdl.Do(func() {
+//          sdl.Init(sdl.INIT_EVERYTHING)
+//      })
+//      ...
+//  }
+func Main(main func()) {
+	mainThread = C.SDL_ThreadID()
+	setEventThread()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		main()
+	}()
+
+	for {
+		select {
+		case f := <-callQueue:
+			f()
+		case <-done:
+			return
+		}
+	}
+}
+
+// Do runs f on the main thread and waits for it to return.  Use it from any
+// goroutine to make video, render, and event calls, which SDL requires to be
+// made from the thread that created the window.  Results can be returned by
+// assigning to variables captured by f.
+//
+// Calling Do from inside a function that is already running on the main
+// thread runs f directly.  If f panics, the panic is passed on to the
+// goroutine that called Do.
+//
+// Do blocks until Main is running.
+func Do(f func()) {
+	if C.SDL_ThreadID() == mainThread {
+		f()
+		return
+	}
+
+	done := make(chan struct{})
+	var p interface{}
+	callQueue <- func() {
+		defer func() {
+			p = recover()
+			close(done)
+		}()
+		f()
+	}
+	<-done
+
+	if p != nil {
+		panic(p)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "timer.h"
+
+// Timers are identified to their callback by a key instead of a pointer to
+// their event, so a callback that is still running when the timer is removed
+// never touches freed memory.
+typedef struct timerEvent {
+	uintptr_t key;
+	SDL_UserEvent event;
+	struct timerEvent *next;
+} timerEvent;
+
+static SDL_SpinLock timerLock;
+static timerEvent *timerEvents;
+static uintptr_t timerNextKey;
+
+// pushTimerEvent runs on the SDL timer thread.  It must not call into Go.
+static Uint32 pushTimerEvent(Uint32 interval, void *param) {
+	uintptr_t key = (uintptr_t)param;
+	SDL_bool found = SDL_FALSE;
+	SDL_Event event;
+	timerEvent *t;
+
+	SDL_zero(event);
+
+	SDL_AtomicLock(&timerLock);
+	for (t = timerEvents; t != NULL; t = t->next) {
+		if (t->key == key) {
+			event.user = t->event;
+			found = SDL_TRUE;
+			break;
+		}
+	}
+	SDL_AtomicUnlock(&timerLock);
+
+	if (!found) {
+		return 0;
+	}
+
+	event.user.timestamp = SDL_GetTicks();
+	SDL_PushEvent(&event);
+	return interval;
+}
+
+SDL_TimerID addTimer(Uint32 interval, SDL_UserEvent *event, uintptr_t *key) {
+	timerEvent *t;
+	SDL_TimerID id;
+
+	t = (timerEvent *)SDL_malloc(sizeof(*t));
+	if (t == NULL) {
+		SDL_OutOfMemory();
+		return 0;
+	}
+	t->event = *event;
+
+	SDL_AtomicLock(&timerLock);
+	t->key = ++timerNextKey;
+	t->next = timerEvents;
+	timerEvents = t;
+	SDL_AtomicUnlock(&timerLock);
+
+	*key = t->key;
+	id = SDL_AddTimer(interval, pushTimerEvent, (void *)t->key);
+	if (id == 0) {
+		removeTimerEvent(t->key);
+	}
+	return id;
+}
+
+void removeTimerEvent(uintptr_t key) {
+	timerEvent **p, *t = NULL;
+
+	SDL_AtomicLock(&timerLock);
+	for (p = &timerEvents; *p != NULL; p = &(*p)->next) {
+		if ((*p)->key == key) {
+			t = *p;
+			*p = t->next;
+			break;
+		}
+	}
+	SDL_AtomicUnlock(&timerLock);
+
+	SDL_free(t);
+}
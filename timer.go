@@ -0,0 +1,101 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+#include "timer.h"
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// GetTicks returns the number of milliseconds since the SDL library
+// initialization.  The value wraps if the program runs for more than ~49
+// days.
+func GetTicks() uint32 {
+	return uint32(C.SDL_GetTicks())
+}
+
+// TicksPassed returns true if the tick value a has passed the tick value b.
+// Use it to compare values returned by GetTicks, it handles the wrap around.
+func TicksPassed(a, b uint32) bool {
+	return int32(b-a) <= 0
+}
+
+// GetPerformanceCounter returns the current value of the high resolution
+// counter.  The counter is only useful for measuring differences, divide the
+// difference by GetPerformanceFrequency to get the time in seconds.
+func GetPerformanceCounter() uint64 {
+	return uint64(C.SDL_GetPerformanceCounter())
+}
+
+// GetPerformanceFrequency returns the count per second of the high resolution
+// counter.
+func GetPerformanceFrequency() uint64 {
+	return uint64(C.SDL_GetPerformanceFrequency())
+}
+
+// Delay waits a specified number of milliseconds before returning.  The
+// delay may be longer than ms due to OS scheduling.
+func Delay(ms uint32) {
+	C.SDL_Delay(C.Uint32(ms))
+}
+
+// TimerID identifies a timer added with AddTimer.
+type TimerID int32
+
+// timerKeys maps each TimerID to the key its event is stored under on the C
+// side.
+var timerKeys = struct {
+	sync.Mutex
+	m map[TimerID]C.uintptr_t
+}{m: make(map[TimerID]C.uintptr_t)}
+
+// AddTimer adds a timer which pushes a copy of event onto the event queue
+// every interval milliseconds, until it is removed with RemoveTimer.  The
+// Timestamp of each pushed event is set when it is pushed.  Use an event
+// type allocated with RegisterEvents and a Code to tell your timers apart.
+//
+// The timer runs on a separate thread and never calls Go code.  The event is
+// delivered to the thread that reads the event queue, so it is safe to act on
+// it like any other event.
+//
+// SDL must be initialized with INIT_TIMER before calling AddTimer.
+func AddTimer(interval uint32, event *UserEvent) (TimerID, error) {
+	var key C.uintptr_t
+	r := C.addTimer(C.Uint32(interval),
+		(*C.SDL_UserEvent)(unsafe.Pointer(event)), &key)
+	if r == 0 {
+		return 0, sdlError(0)
+	}
+
+	id := TimerID(r)
+	timerKeys.Lock()
+	timerKeys.m[id] = key
+	timerKeys.Unlock()
+	return id, nil
+}
+
+// RemoveTimer removes a timer added with AddTimer.  It returns false if the
+// timer was not found.  Events that were pushed before the timer was removed
+// stay in the event queue.
+func RemoveTimer(id TimerID) bool {
+	timerKeys.Lock()
+	key, ok := timerKeys.m[id]
+	delete(timerKeys.m, id)
+	timerKeys.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	r := C.SDL_RemoveTimer(C.SDL_TimerID(id))
+	C.removeTimerEvent(key)
+	return r == C.SDL_TRUE
+}
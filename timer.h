@@ -0,0 +1,8 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "SDL.h"
+
+extern SDL_TimerID addTimer(Uint32 interval, SDL_UserEvent *event, uintptr_t *key);
+extern void removeTimerEvent(uintptr_t key);
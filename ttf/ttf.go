@@ -95,6 +95,30 @@ func OpenFontIndex(file string, ptsize, index int) (Font, error) {
 	return Font{f}, nil
 }
 
+// OpenFontRW opens a font from src and creates a font of the specified point
+// size.  The font keeps reading from src while it is in use, so src must stay
+// open until the font is closed.  If freesrc is true src is closed when the
+// font is closed, or right away if there is an error.
+func OpenFontRW(src *sdl.RWops, freesrc bool, ptsize int) (Font, error) {
+	return OpenFontIndexRW(src, freesrc, ptsize, 0)
+}
+
+// OpenFontIndexRW is the same as OpenFontRW, but uses the font face at
+// index.
+func OpenFontIndexRW(src *sdl.RWops, freesrc bool, ptsize, index int) (Font, error) {
+	var fs C.int
+	if freesrc {
+		fs = 1
+	}
+
+	f := C.TTF_OpenFontIndexRW((*C.SDL_RWops)(unsafe.Pointer(src)), fs,
+		C.int(ptsize), C.long(index))
+	if f == nil {
+		return Font{}, sdlError(0)
+	}
+	return Font{f}, nil
+}
+
 type Style int
 
 // Font style settings.
@@ -6,6 +6,7 @@ package sdl
 
 /*
 #include "SDL.h"
+#include "compat.h"
 */
 import "C"
 
@@ -86,6 +87,15 @@ const (
 	WINDOWEVENT_CLOSE WindowEventID = C.SDL_WINDOWEVENT_CLOSE
 )
 
+type DisplayEventID uint8
+
+const (
+	// never used
+	DISPLAYEVENT_NONE DisplayEventID = C.SDL_DISPLAYEVENT_NONE
+	// Display orientation has changed to Data1
+	DISPLAYEVENT_ORIENTATION DisplayEventID = C.SDL_DISPLAYEVENT_ORIENTATION
+)
+
 type GLattr uint32
 
 const (
@@ -0,0 +1,210 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+/*
+#include "SDL.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// AudioBuffer holds audio samples in the format of an AudioSpec.
+type AudioBuffer []byte
+
+// Int16 returns the samples of b as int16s, without copying.  Use it when
+// the format of b is AUDIO_S16SYS.
+func (b AudioBuffer) Int16() []int16 {
+	var s []int16
+	if len(b) < 2 {
+		return s
+	}
+	sh := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b) / 2
+	sh.Cap = len(b) / 2
+	return s
+}
+
+// Float32 returns the samples of b as float32s, without copying.  Use it when
+// the format of b is AUDIO_F32SYS.
+func (b AudioBuffer) Float32() []float32 {
+	var s []float32
+	if len(b) < 4 {
+		return s
+	}
+	sh := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b) / 4
+	sh.Cap = len(b) / 4
+	return s
+}
+
+// LoadWAV_RW loads a WAV from src.  It returns the spec of the audio, with
+// Samples set to 4096, and a copy of the samples.  If freesrc is true src is
+// closed, even when an error is returned.
+func LoadWAV_RW(src *RWops, freesrc bool) (*AudioSpec, AudioBuffer, error) {
+	var f C.int
+	if freesrc {
+		f = 1
+	}
+
+	var cspec C.SDL_AudioSpec
+	var cbuf *C.Uint8
+	var clen C.Uint32
+	if C.SDL_LoadWAV_RW(src.cptr(), f, &cspec, &cbuf, &clen) == nil {
+		return nil, nil, sdlError(0)
+	}
+	defer C.SDL_FreeWAV(cbuf)
+
+	buf := make(AudioBuffer, int(clen))
+	copy(buf, byteSlice(unsafe.Pointer(cbuf), int(clen)))
+	return goAudioSpec(&cspec), buf, nil
+}
+
+// LoadWAV loads the named WAV file, see LoadWAV_RW.
+func LoadWAV(file string) (*AudioSpec, AudioBuffer, error) {
+	src, err := RWFromFile(file, "rb")
+	if err != nil {
+		return nil, nil, err
+	}
+	return LoadWAV_RW(src, true)
+}
+
+const wavHeaderSize = 44
+
+var errWAVFormat = errors.New("WAV: format must be AUDIO_U8, AUDIO_S16LSB, AUDIO_S32LSB, or AUDIO_F32LSB")
+
+// WAVWriter writes audio to a WAV file as it is produced.  The sizes in the
+// header are filled in by Close, so the writer must be able to seek.
+type WAVWriter struct {
+	w    io.WriteSeeker
+	n    int64 // Bytes of audio written
+	done bool
+}
+
+// NewWAVWriter writes the header of a WAV file holding audio in the format of
+// spec to w, and returns a WAVWriter that writes the audio after it.  Only
+// little-endian formats that WAV files support can be written.
+func NewWAVWriter(w io.WriteSeeker, spec *AudioSpec) (*WAVWriter, error) {
+	var tag uint16
+	switch spec.Format {
+	case AUDIO_U8, AUDIO_S16LSB, AUDIO_S32LSB:
+		tag = 1 // PCM
+	case AUDIO_F32LSB:
+		tag = 3 // IEEE float
+	default:
+		return nil, errWAVFormat
+	}
+
+	frame := uint32(spec.Channels) * uint32(spec.Format.BitSize()/8)
+	h := struct {
+		Riff       [4]byte
+		RiffSize   uint32
+		Wave       [4]byte
+		Fmt        [4]byte
+		FmtSize    uint32
+		Tag        uint16
+		Channels   uint16
+		Freq       uint32
+		ByteRate   uint32
+		BlockAlign uint16
+		Bits       uint16
+		Data       [4]byte
+		DataSize   uint32
+	}{
+		Riff:       [4]byte{'R', 'I', 'F', 'F'},
+		Wave:       [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:        [4]byte{'f', 'm', 't', ' '},
+		FmtSize:    16,
+		Tag:        tag,
+		Channels:   uint16(spec.Channels),
+		Freq:       uint32(spec.Freq),
+		ByteRate:   uint32(spec.Freq) * frame,
+		BlockAlign: uint16(frame),
+		Bits:       uint16(spec.Format.BitSize()),
+		Data:       [4]byte{'d', 'a', 't', 'a'},
+	}
+	if err := binary.Write(w, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	return &WAVWriter{w: w}, nil
+}
+
+// Write writes audio in the format given to NewWAVWriter.
+func (ww *WAVWriter) Write(p []byte) (int, error) {
+	n, err := ww.w.Write(p)
+	ww.n += int64(n)
+	return n, err
+}
+
+// Close fills in the sizes in the header.  It does not close the underlying
+// writer.
+func (ww *WAVWriter) Close() error {
+	if ww.done {
+		return nil
+	}
+	ww.done = true
+
+	// A RIFF chunk is padded to an even size.
+	if ww.n%2 != 0 {
+		if _, err := ww.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := ww.w.Seek(4, io.SeekStart); err != nil {
+		return err
+	}
+	riff := uint32(wavHeaderSize - 8 + ww.n + ww.n%2)
+	if err := binary.Write(ww.w, binary.LittleEndian, riff); err != nil {
+		return err
+	}
+	if _, err := ww.w.Seek(wavHeaderSize-4, io.SeekStart); err != nil {
+		return err
+	}
+	if err := binary.Write(ww.w, binary.LittleEndian, uint32(ww.n)); err != nil {
+		return err
+	}
+	_, err := ww.w.Seek(0, io.SeekEnd)
+	return err
+}
+
+// SaveWAV_RW writes data, audio in the format of spec, to dst as a WAV file.
+// If freedst is true dst is closed, even when an error is returned.
+func SaveWAV_RW(dst *RWops, freedst bool, spec *AudioSpec, data []byte) (err error) {
+	if freedst {
+		defer func() {
+			if cerr := dst.Close(); err == nil {
+				err = cerr
+			}
+		}()
+	}
+
+	ww, err := NewWAVWriter(dst, spec)
+	if err != nil {
+		return err
+	}
+	if _, err := ww.Write(data); err != nil {
+		return err
+	}
+	return ww.Close()
+}
+
+// SaveWAV writes data, audio in the format of spec, to the named file as a
+// WAV file.
+func SaveWAV(file string, spec *AudioSpec, data []byte) error {
+	dst, err := RWFromFile(file, "wb")
+	if err != nil {
+		return err
+	}
+	return SaveWAV_RW(dst, true, spec, data)
+}
@@ -0,0 +1,78 @@
+// Copyright 2012 The go-sdl2 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// seekBuffer is an in-memory io.WriteSeeker.
+type seekBuffer struct {
+	buf []byte
+	pos int64
+}
+
+func (b *seekBuffer) Write(p []byte) (int, error) {
+	if end := b.pos + int64(len(p)); end > int64(len(b.buf)) {
+		b.buf = append(b.buf, make([]byte, end-int64(len(b.buf)))...)
+	}
+	n := copy(b.buf[b.pos:], p)
+	b.pos += int64(n)
+	return n, nil
+}
+
+func (b *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += b.pos
+	case io.SeekEnd:
+		offset += int64(len(b.buf))
+	}
+	b.pos = offset
+	return offset, nil
+}
+
+func TestWAVWriter(t *testing.T) {
+	var b seekBuffer
+	spec := &AudioSpec{Freq: 22050, Format: AUDIO_S16LSB, Channels: 1}
+	ww, err := NewWAVWriter(&b, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ww.Write([]byte{1, 2, 3, 4})
+	ww.Write([]byte{5, 6})
+	if err := ww.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.buf) != wavHeaderSize+6 {
+		t.Fatalf("file is %d bytes, want %d", len(b.buf), wavHeaderSize+6)
+	}
+	if !bytes.Equal(b.buf[:4], []byte("RIFF")) || !bytes.Equal(b.buf[8:12], []byte("WAVE")) {
+		t.Errorf("bad RIFF header % x", b.buf[:12])
+	}
+	if n := binary.LittleEndian.Uint32(b.buf[4:]); n != wavHeaderSize-8+6 {
+		t.Errorf("RIFF size = %d, want %d", n, wavHeaderSize-8+6)
+	}
+	if n := binary.LittleEndian.Uint32(b.buf[40:]); n != 6 {
+		t.Errorf("data size = %d, want 6", n)
+	}
+	if rate := binary.LittleEndian.Uint32(b.buf[28:]); rate != 44100 {
+		t.Errorf("byte rate = %d, want 44100", rate)
+	}
+	if !bytes.Equal(b.buf[wavHeaderSize:], []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("data = % x", b.buf[wavHeaderSize:])
+	}
+}
+
+func TestWAVWriterFormat(t *testing.T) {
+	var b seekBuffer
+	if _, err := NewWAVWriter(&b, &AudioSpec{Format: AUDIO_S16MSB}); err == nil {
+		t.Errorf("big-endian format accepted")
+	}
+}